@@ -10,6 +10,9 @@ import Users from './pages/Users';
 import Operators from './pages/Operators';
 import Admins from './pages/Admins';
 import Settings from './pages/Settings';
+import Transactions from './pages/Transactions';
+import Funding from './pages/Funding';
+import Adjustments from './pages/Adjustments';
 import './App.css';
 
 const queryClient = new QueryClient();
@@ -57,6 +60,30 @@ const router = createBrowserRouter([
         )
       },
       { path: 'users', element: <Users /> },
+      {
+        path: 'transactions',
+        element: (
+          <ProtectedRoute allowedRoles={['super_admin', 'admin']}>
+            <Transactions />
+          </ProtectedRoute>
+        )
+      },
+      {
+        path: 'funding',
+        element: (
+          <ProtectedRoute allowedRoles={['super_admin', 'admin']}>
+            <Funding />
+          </ProtectedRoute>
+        )
+      },
+      {
+        path: 'adjustments',
+        element: (
+          <ProtectedRoute allowedRoles={['super_admin', 'admin']}>
+            <Adjustments />
+          </ProtectedRoute>
+        )
+      },
       {
         path: 'operators',
         element: (
@@ -122,7 +122,7 @@ export const authApi = {
 export const gameApi = {
     getCurrentRound: () => request<any>('/api/v1/games/pc28/round/current', {}, false),
     getHistory: () => request<any[]>('/api/v1/games/pc28/history', {}, false),
-    getOdds: () => request<Record<string, number>>('/api/v1/games/pc28/odds', {}, false),
+    getOdds: () => request<{ odds: Record<string, number>; number_odds: Record<string, number> }>('/api/v1/games/pc28/odds', {}, false),
 };
 
 // ==========================================
@@ -154,6 +154,96 @@ export const userApi = {
     list: () => request<any[]>('/api/v1/users'),
 };
 
+// ==========================================
+// Wallet API (super_admin / admin)
+// ==========================================
+
+export interface WalletTransaction {
+    ID: number;
+    CreatedAt: string;
+    user_id: number;
+    username: string;
+    type: string;
+    amount: number;
+    balance_after: number;
+    bet_id?: number;
+    round_id?: number;
+    ref_type?: string;
+    ref_id?: number;
+    note?: string;
+}
+
+export interface TransactionQuery {
+    username?: string;
+    type?: string;
+    round_id?: string;
+    bet_id?: string;
+    start_date?: string;
+    end_date?: string;
+    page?: number;
+}
+
+export const walletApi = {
+    transactions: (q: TransactionQuery) => {
+        const params = new URLSearchParams();
+        Object.entries(q).forEach(([k, v]) => {
+            if (v !== undefined && v !== '') params.append(k, String(v));
+        });
+        return request<{ data: WalletTransaction[]; total: number; page: number; page_size: number }>(
+            `/api/v1/admin/transactions?${params.toString()}`
+        );
+    },
+};
+
+// ==========================================
+// Funding API (super_admin / admin)
+// ==========================================
+
+export interface FundingRequest {
+    ID: number;
+    CreatedAt: string;
+    user_id: number;
+    username: string;
+    type: 'deposit' | 'withdrawal';
+    amount: number;
+    status: 'pending' | 'approved' | 'rejected';
+    method: string;
+    account: string;
+    note?: string;
+    reviewed_by_id?: number;
+    reviewed_at?: string;
+    review_note?: string;
+}
+
+export interface FundingQuery {
+    username?: string;
+    type?: string;
+    status?: string;
+    page?: number;
+}
+
+export const fundingApi = {
+    list: (q: FundingQuery) => {
+        const params = new URLSearchParams();
+        Object.entries(q).forEach(([k, v]) => {
+            if (v !== undefined && v !== '') params.append(k, String(v));
+        });
+        return request<{ data: FundingRequest[]; total: number; page: number; page_size: number }>(
+            `/api/v1/admin/funding?${params.toString()}`
+        );
+    },
+    approve: (id: number, note: string) =>
+        request<FundingRequest>(`/api/v1/admin/funding/${id}/approve`, {
+            method: 'POST',
+            body: JSON.stringify({ note }),
+        }),
+    reject: (id: number, note: string) =>
+        request<FundingRequest>(`/api/v1/admin/funding/${id}/reject`, {
+            method: 'POST',
+            body: JSON.stringify({ note }),
+        }),
+};
+
 // ==========================================
 // Admin API (super_admin only)
 // ==========================================
@@ -166,3 +256,83 @@ export const adminApi = {
             body: JSON.stringify(data),
         }),
 };
+
+// ==========================================
+// Settings API (super_admin only)
+// ==========================================
+
+export interface GameSettings {
+    round_duration: number;
+    betting_window: number;
+    bet_cutoff: number;
+    bet_cancel: boolean;
+    cancel_limit: number;
+    min_bet: number;
+    max_bet: number;
+    odds: Record<string, number>;
+    number_odds: Record<string, number>;
+}
+
+export const settingsApi = {
+    get: () => request<GameSettings>('/api/v1/admin/settings'),
+    update: (data: GameSettings) =>
+        request<GameSettings>('/api/v1/admin/settings', {
+            method: 'PUT',
+            body: JSON.stringify(data),
+        }),
+};
+
+// ==========================================
+// Adjustment API (super_admin / admin)
+// ==========================================
+
+export type AdjustmentReason = 'compensation' | 'correction' | 'promotion' | 'recovery' | 'other';
+
+export interface BalanceAdjustment {
+    ID: number;
+    CreatedAt: string;
+    user_id: number;
+    username: string;
+    amount: number; // Positive credits, negative debits
+    reason: AdjustmentReason;
+    note: string;
+    status: 'pending' | 'applied' | 'rejected';
+    requested_by_id: number;
+    reviewed_by_id?: number;
+    reviewed_at?: string;
+    review_note?: string;
+}
+
+export interface AdjustmentQuery {
+    username?: string;
+    reason?: string;
+    status?: string;
+    page?: number;
+}
+
+export const adjustmentApi = {
+    list: (q: AdjustmentQuery) => {
+        const params = new URLSearchParams();
+        Object.entries(q).forEach(([k, v]) => {
+            if (v !== undefined && v !== '') params.append(k, String(v));
+        });
+        return request<{ data: BalanceAdjustment[]; total: number; page: number; page_size: number }>(
+            `/api/v1/admin/adjustments?${params.toString()}`
+        );
+    },
+    create: (data: { user_id: number; amount: number; reason: AdjustmentReason; note: string }) =>
+        request<{ adjustment: BalanceAdjustment; balance: number }>('/api/v1/admin/adjustments', {
+            method: 'POST',
+            body: JSON.stringify(data),
+        }),
+    approve: (id: number, note: string) =>
+        request<BalanceAdjustment>(`/api/v1/admin/adjustments/${id}/approve`, {
+            method: 'POST',
+            body: JSON.stringify({ note }),
+        }),
+    reject: (id: number, note: string) =>
+        request<BalanceAdjustment>(`/api/v1/admin/adjustments/${id}/reject`, {
+            method: 'POST',
+            body: JSON.stringify({ note }),
+        }),
+};
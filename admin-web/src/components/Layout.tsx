@@ -22,6 +22,9 @@ export default function Layout() {
         { path: '/', icon: '📊', label: '仪表盘', roles: ['super_admin', 'admin', 'operator'] },
         { path: '/rounds', icon: '🎲', label: '轮次管理', roles: ['super_admin', 'admin'] },
         { path: '/users', icon: '👥', label: '用户管理', roles: ['super_admin', 'admin', 'operator'] },
+        { path: '/transactions', icon: '💰', label: '账变记录', roles: ['super_admin', 'admin'] },
+        { path: '/funding', icon: '🏦', label: '充值提现', roles: ['super_admin', 'admin'] },
+        { path: '/adjustments', icon: '🧾', label: '人工调账', roles: ['super_admin', 'admin'] },
         { path: '/operators', icon: '🏢', label: '运营者管理', roles: ['super_admin', 'admin'] },
         { path: '/admins', icon: '🔑', label: '管理员管理', roles: ['super_admin'] },
         { path: '/settings', icon: '⚙️', label: '设置', roles: ['super_admin'] },
@@ -0,0 +1,268 @@
+import { useState } from 'react';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import { useAtomValue } from 'jotai';
+import { adminUserAtom } from '../store/atoms';
+import { adjustmentApi, type AdjustmentQuery, type AdjustmentReason, type BalanceAdjustment } from '../api/client';
+import './Users.css';
+import './Transactions.css';
+import './Funding.css';
+import './Adjustments.css';
+
+const reasonLabels: Record<string, string> = {
+    compensation: '补偿',
+    correction: '差错更正',
+    promotion: '活动赠送',
+    recovery: '追回',
+    other: '其他',
+};
+
+const statusLabels: Record<string, string> = {
+    pending: '待审批',
+    applied: '已生效',
+    rejected: '已拒绝',
+};
+
+const emptyForm = { user_id: '', amount: '', reason: 'compensation' as AdjustmentReason, note: '' };
+
+export default function Adjustments() {
+    const adminUser = useAtomValue(adminUserAtom);
+    const queryClient = useQueryClient();
+    const [filters, setFilters] = useState<AdjustmentQuery>({});
+    const [query, setQuery] = useState<AdjustmentQuery>({ page: 1 });
+    const [showModal, setShowModal] = useState(false);
+    const [form, setForm] = useState(emptyForm);
+    const [formError, setFormError] = useState('');
+
+    const { data, isLoading } = useQuery({
+        queryKey: ['adjustments', query],
+        queryFn: async () => {
+            const res = await adjustmentApi.list(query);
+            return res.data;
+        },
+    });
+
+    const createMutation = useMutation({
+        mutationFn: (data: { user_id: number; amount: number; reason: AdjustmentReason; note: string }) =>
+            adjustmentApi.create(data),
+        onSuccess: (res) => {
+            if (res.error) {
+                setFormError(res.error);
+                return;
+            }
+            if (res.data?.adjustment.status === 'pending') {
+                alert('金额超过审批阈值，已提交等待另一位管理员审批');
+            }
+            queryClient.invalidateQueries({ queryKey: ['adjustments'] });
+            setShowModal(false);
+            setForm(emptyForm);
+        },
+    });
+
+    const reviewMutation = useMutation({
+        mutationFn: ({ id, approve, note }: { id: number; approve: boolean; note: string }) =>
+            approve ? adjustmentApi.approve(id, note) : adjustmentApi.reject(id, note),
+        onSuccess: (res) => {
+            if (res.error) {
+                alert(`操作失败: ${res.error}`);
+            }
+            queryClient.invalidateQueries({ queryKey: ['adjustments'] });
+        },
+    });
+
+    const submit = () => {
+        const userId = Number(form.user_id);
+        if (!Number.isInteger(userId) || userId <= 0) {
+            setFormError('请输入有效的用户 ID');
+            return;
+        }
+        if (!/^-?\d+(\.\d{1,2})?$/.test(form.amount) || Number(form.amount) === 0) {
+            setFormError('请输入非零金额 (最多两位小数，负数为扣款)');
+            return;
+        }
+        if (!form.note.trim()) {
+            setFormError('请填写备注');
+            return;
+        }
+        setFormError('');
+        createMutation.mutate({ user_id: userId, amount: Number(form.amount), reason: form.reason, note: form.note.trim() });
+    };
+
+    const review = (adj: BalanceAdjustment, approve: boolean) => {
+        const action = approve ? '批准' : '拒绝';
+        const note = window.prompt(`${action}调账 ¥${adj.amount.toLocaleString()} (${adj.username})，备注:`, '');
+        if (note === null) return;
+        reviewMutation.mutate({ id: adj.ID, approve, note });
+    };
+
+    const page = data?.page || 1;
+    const pages = data ? Math.max(1, Math.ceil(data.total / data.page_size)) : 1;
+
+    return (
+        <div className="users-page">
+            <div className="page-header">
+                <h1 className="page-title">人工调账</h1>
+                <button className="btn btn-primary" onClick={() => setShowModal(true)}>
+                    + 新建调账
+                </button>
+            </div>
+
+            <div className="search-bar transactions-filters">
+                <input
+                    className="search-input"
+                    placeholder="用户名"
+                    value={filters.username || ''}
+                    onChange={(e) => setFilters({ ...filters, username: e.target.value })}
+                />
+                <select
+                    className="search-input"
+                    value={filters.reason || ''}
+                    onChange={(e) => setFilters({ ...filters, reason: e.target.value })}
+                >
+                    <option value="">全部原因</option>
+                    {Object.entries(reasonLabels).map(([value, label]) => (
+                        <option key={value} value={value}>{label}</option>
+                    ))}
+                </select>
+                <select
+                    className="search-input"
+                    value={filters.status || ''}
+                    onChange={(e) => setFilters({ ...filters, status: e.target.value })}
+                >
+                    <option value="">全部状态</option>
+                    {Object.entries(statusLabels).map(([value, label]) => (
+                        <option key={value} value={value}>{label}</option>
+                    ))}
+                </select>
+                <button className="btn btn-primary" onClick={() => setQuery({ ...filters, page: 1 })}>
+                    搜索
+                </button>
+            </div>
+
+            {isLoading ? (
+                <div className="loading">加载中...</div>
+            ) : !data || data.data.length === 0 ? (
+                <div className="empty">
+                    <span className="empty-icon">🧾</span>
+                    <p>暂无调账记录</p>
+                </div>
+            ) : (
+                <div className="users-table-wrapper">
+                    <table className="users-table">
+                        <thead>
+                            <tr>
+                                <th>时间</th>
+                                <th>用户名</th>
+                                <th>金额</th>
+                                <th>原因</th>
+                                <th>备注</th>
+                                <th>状态</th>
+                                <th>操作</th>
+                            </tr>
+                        </thead>
+                        <tbody>
+                            {data.data.map((adj: BalanceAdjustment) => (
+                                <tr key={adj.ID}>
+                                    <td>{new Date(adj.CreatedAt).toLocaleString()}</td>
+                                    <td className="username">{adj.username}</td>
+                                    <td className="balance">{adj.amount > 0 ? '+' : ''}{adj.amount.toLocaleString()}</td>
+                                    <td>{reasonLabels[adj.reason] || adj.reason}</td>
+                                    <td>{adj.note}</td>
+                                    <td>
+                                        <span className={`funding-status ${adj.status === 'applied' ? 'approved' : adj.status}`}>
+                                            {statusLabels[adj.status] || adj.status}
+                                        </span>
+                                        {adj.review_note && <div className="review-note">{adj.review_note}</div>}
+                                    </td>
+                                    <td>
+                                        {adj.status !== 'pending' ? (
+                                            <span className="no-data">-</span>
+                                        ) : adj.requested_by_id === adminUser?.id ? (
+                                            <span className="no-data">等待其他管理员审批</span>
+                                        ) : (
+                                            <div className="funding-actions">
+                                                <button className="btn btn-primary" disabled={reviewMutation.isPending} onClick={() => review(adj, true)}>
+                                                    批准
+                                                </button>
+                                                <button className="btn btn-secondary" disabled={reviewMutation.isPending} onClick={() => review(adj, false)}>
+                                                    拒绝
+                                                </button>
+                                            </div>
+                                        )}
+                                    </td>
+                                </tr>
+                            ))}
+                        </tbody>
+                    </table>
+                </div>
+            )}
+
+            {data && pages > 1 && (
+                <div className="pagination">
+                    <button className="btn btn-secondary" disabled={page <= 1} onClick={() => setQuery({ ...query, page: page - 1 })}>
+                        上一页
+                    </button>
+                    <span>{page} / {pages}</span>
+                    <button className="btn btn-secondary" disabled={page >= pages} onClick={() => setQuery({ ...query, page: page + 1 })}>
+                        下一页
+                    </button>
+                </div>
+            )}
+
+            {/* Create Modal */}
+            {showModal && (
+                <div className="modal-overlay" onClick={() => setShowModal(false)}>
+                    <div className="modal" onClick={(e) => e.stopPropagation()}>
+                        <h2>新建调账</h2>
+                        <div className="form-group">
+                            <label>用户 ID</label>
+                            <input
+                                type="number"
+                                value={form.user_id}
+                                onChange={(e) => setForm({ ...form, user_id: e.target.value })}
+                            />
+                        </div>
+                        <div className="form-group">
+                            <label>金额</label>
+                            <input
+                                type="number"
+                                step="0.01"
+                                value={form.amount}
+                                onChange={(e) => setForm({ ...form, amount: e.target.value })}
+                                placeholder="正数加款，负数扣款"
+                            />
+                        </div>
+                        <div className="form-group">
+                            <label>原因</label>
+                            <select
+                                value={form.reason}
+                                onChange={(e) => setForm({ ...form, reason: e.target.value as AdjustmentReason })}
+                            >
+                                {Object.entries(reasonLabels).map(([value, label]) => (
+                                    <option key={value} value={value}>{label}</option>
+                                ))}
+                            </select>
+                        </div>
+                        <div className="form-group">
+                            <label>备注</label>
+                            <input
+                                type="text"
+                                maxLength={255}
+                                value={form.note}
+                                onChange={(e) => setForm({ ...form, note: e.target.value })}
+                            />
+                        </div>
+                        {formError && <p className="form-error">{formError}</p>}
+                        <div className="modal-actions">
+                            <button className="btn btn-secondary" onClick={() => setShowModal(false)}>
+                                取消
+                            </button>
+                            <button className="btn btn-primary" disabled={createMutation.isPending} onClick={submit}>
+                                提交
+                            </button>
+                        </div>
+                    </div>
+                </div>
+            )}
+        </div>
+    );
+}
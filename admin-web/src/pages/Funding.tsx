@@ -0,0 +1,160 @@
+import { useState } from 'react';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import { fundingApi, type FundingQuery, type FundingRequest } from '../api/client';
+import './Users.css';
+import './Transactions.css';
+import './Funding.css';
+
+const typeLabels: Record<string, string> = {
+    deposit: '充值',
+    withdrawal: '提现',
+};
+
+const statusLabels: Record<string, string> = {
+    pending: '待审核',
+    approved: '已批准',
+    rejected: '已拒绝',
+};
+
+export default function Funding() {
+    const queryClient = useQueryClient();
+    const [filters, setFilters] = useState<FundingQuery>({ status: 'pending' });
+    const [query, setQuery] = useState<FundingQuery>({ status: 'pending', page: 1 });
+
+    const { data, isLoading } = useQuery({
+        queryKey: ['funding', query],
+        queryFn: async () => {
+            const res = await fundingApi.list(query);
+            return res.data;
+        },
+    });
+
+    const reviewMutation = useMutation({
+        mutationFn: ({ id, approve, note }: { id: number; approve: boolean; note: string }) =>
+            approve ? fundingApi.approve(id, note) : fundingApi.reject(id, note),
+        onSuccess: (res) => {
+            if (res.error) {
+                alert(`操作失败: ${res.error}`);
+            }
+            queryClient.invalidateQueries({ queryKey: ['funding'] });
+        },
+    });
+
+    const review = (req: FundingRequest, approve: boolean) => {
+        const action = approve ? '批准' : '拒绝';
+        const note = window.prompt(`${action}${typeLabels[req.type]} ¥${req.amount.toLocaleString()} (${req.username})，备注:`, '');
+        if (note === null) return;
+        reviewMutation.mutate({ id: req.ID, approve, note });
+    };
+
+    const page = data?.page || 1;
+    const pages = data ? Math.max(1, Math.ceil(data.total / data.page_size)) : 1;
+
+    return (
+        <div className="users-page">
+            <div className="page-header">
+                <h1 className="page-title">充值提现</h1>
+            </div>
+
+            <div className="search-bar transactions-filters">
+                <input
+                    className="search-input"
+                    placeholder="用户名"
+                    value={filters.username || ''}
+                    onChange={(e) => setFilters({ ...filters, username: e.target.value })}
+                />
+                <select
+                    className="search-input"
+                    value={filters.type || ''}
+                    onChange={(e) => setFilters({ ...filters, type: e.target.value })}
+                >
+                    <option value="">全部类型</option>
+                    {Object.entries(typeLabels).map(([value, label]) => (
+                        <option key={value} value={value}>{label}</option>
+                    ))}
+                </select>
+                <select
+                    className="search-input"
+                    value={filters.status || ''}
+                    onChange={(e) => setFilters({ ...filters, status: e.target.value })}
+                >
+                    <option value="">全部状态</option>
+                    {Object.entries(statusLabels).map(([value, label]) => (
+                        <option key={value} value={value}>{label}</option>
+                    ))}
+                </select>
+                <button className="btn btn-primary" onClick={() => setQuery({ ...filters, page: 1 })}>
+                    搜索
+                </button>
+            </div>
+
+            {isLoading ? (
+                <div className="loading">加载中...</div>
+            ) : !data || data.data.length === 0 ? (
+                <div className="empty">
+                    <span className="empty-icon">🏦</span>
+                    <p>暂无申请</p>
+                </div>
+            ) : (
+                <div className="users-table-wrapper">
+                    <table className="users-table">
+                        <thead>
+                            <tr>
+                                <th>时间</th>
+                                <th>用户名</th>
+                                <th>类型</th>
+                                <th>金额</th>
+                                <th>方式 / 账户</th>
+                                <th>备注</th>
+                                <th>状态</th>
+                                <th>操作</th>
+                            </tr>
+                        </thead>
+                        <tbody>
+                            {data.data.map((req: FundingRequest) => (
+                                <tr key={req.ID}>
+                                    <td>{new Date(req.CreatedAt).toLocaleString()}</td>
+                                    <td className="username">{req.username}</td>
+                                    <td>{typeLabels[req.type] || req.type}</td>
+                                    <td className="balance">¥{req.amount.toLocaleString()}</td>
+                                    <td>{req.method} / {req.account}</td>
+                                    <td>{req.note || <span className="no-data">-</span>}</td>
+                                    <td>
+                                        <span className={`funding-status ${req.status}`}>{statusLabels[req.status] || req.status}</span>
+                                        {req.review_note && <div className="review-note">{req.review_note}</div>}
+                                    </td>
+                                    <td>
+                                        {req.status === 'pending' ? (
+                                            <div className="funding-actions">
+                                                <button className="btn btn-primary" disabled={reviewMutation.isPending} onClick={() => review(req, true)}>
+                                                    批准
+                                                </button>
+                                                <button className="btn btn-secondary" disabled={reviewMutation.isPending} onClick={() => review(req, false)}>
+                                                    拒绝
+                                                </button>
+                                            </div>
+                                        ) : (
+                                            <span className="no-data">-</span>
+                                        )}
+                                    </td>
+                                </tr>
+                            ))}
+                        </tbody>
+                    </table>
+                </div>
+            )}
+
+            {data && pages > 1 && (
+                <div className="pagination">
+                    <button className="btn btn-secondary" disabled={page <= 1} onClick={() => setQuery({ ...query, page: page - 1 })}>
+                        上一页
+                    </button>
+                    <span>{page} / {pages}</span>
+                    <button className="btn btn-secondary" disabled={page >= pages} onClick={() => setQuery({ ...query, page: page + 1 })}>
+                        下一页
+                    </button>
+                </div>
+            )}
+        </div>
+    );
+}
@@ -1,20 +1,92 @@
-import { useState } from 'react';
+import { useEffect, useState } from 'react';
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import { settingsApi, type GameSettings } from '../api/client';
 import './Settings.css';
 
+const betTypeLabels: Record<string, string> = {
+    big: '大 (14-27)',
+    small: '小 (0-13)',
+    odd: '单',
+    even: '双',
+    big_odd: '大单',
+    big_even: '大双',
+    small_odd: '小单',
+    small_even: '小双',
+    extreme_big: '极大 (22-27)',
+    extreme_small: '极小 (0-5)',
+    leopard: '豹子',
+    pair: '对子',
+    straight: '顺子',
+    digit_a: '定位 A',
+    digit_b: '定位 B',
+    digit_c: '定位 C',
+};
+
 export default function Settings() {
+    const queryClient = useQueryClient();
     const [settings, setSettings] = useState({
         roundDuration: 60,
         bettingWindow: 55,
+        betCutoff: 2,
+        betCancel: true,
+        cancelLimit: 0,
         maxBetAmount: 10000,
         minBetAmount: 10,
         enableMockData: true,
         kenoApiUrl: '',
         kenoApiKey: '',
     });
+    const [odds, setOdds] = useState<Record<string, number>>({});
+    const [numberOdds, setNumberOdds] = useState<Record<string, number>>({});
+
+    const { data: saved } = useQuery({
+        queryKey: ['settings'],
+        queryFn: async () => {
+            const res = await settingsApi.get();
+            return res.data;
+        },
+    });
+
+    useEffect(() => {
+        if (!saved) return;
+        setSettings((s) => ({
+            ...s,
+            roundDuration: saved.round_duration,
+            bettingWindow: saved.betting_window,
+            betCutoff: saved.bet_cutoff,
+            betCancel: saved.bet_cancel,
+            cancelLimit: saved.cancel_limit,
+            maxBetAmount: saved.max_bet,
+            minBetAmount: saved.min_bet,
+        }));
+        setOdds(saved.odds);
+        setNumberOdds(saved.number_odds);
+    }, [saved]);
+
+    const saveMutation = useMutation({
+        mutationFn: (data: GameSettings) => settingsApi.update(data),
+        onSuccess: (res) => {
+            if (res.error) {
+                alert(`保存失败: ${res.error}`);
+                return;
+            }
+            queryClient.invalidateQueries({ queryKey: ['settings'] });
+            alert('设置已保存，将从下一轮开始生效');
+        },
+    });
 
     const handleSave = () => {
-        // TODO: Save settings to backend
-        alert('设置已保存');
+        saveMutation.mutate({
+            round_duration: settings.roundDuration,
+            betting_window: settings.bettingWindow,
+            bet_cutoff: settings.betCutoff,
+            bet_cancel: settings.betCancel,
+            cancel_limit: settings.cancelLimit,
+            min_bet: settings.minBetAmount,
+            max_bet: settings.maxBetAmount,
+            odds,
+            number_odds: numberOdds,
+        });
     };
 
     return (
@@ -42,10 +114,36 @@ export default function Settings() {
                                 onChange={(e) => setSettings({ ...settings, bettingWindow: +e.target.value })}
                             />
                         </div>
+                        <div className="setting-item">
+                            <label>截止安全余量 (秒)</label>
+                            <input
+                                type="number"
+                                value={settings.betCutoff}
+                                onChange={(e) => setSettings({ ...settings, betCutoff: +e.target.value })}
+                            />
+                        </div>
+                        <div className="setting-item toggle">
+                            <label>允许撤单</label>
+                            <button
+                                className={`toggle-btn ${settings.betCancel ? 'active' : ''}`}
+                                onClick={() => setSettings({ ...settings, betCancel: !settings.betCancel })}
+                            >
+                                {settings.betCancel ? '开启' : '关闭'}
+                            </button>
+                        </div>
+                        <div className="setting-item">
+                            <label>每日撤单上限 (0 不限)</label>
+                            <input
+                                type="number"
+                                value={settings.cancelLimit}
+                                onChange={(e) => setSettings({ ...settings, cancelLimit: +e.target.value })}
+                            />
+                        </div>
                         <div className="setting-item">
                             <label>最大投注额</label>
                             <input
                                 type="number"
+                                step="0.01"
                                 value={settings.maxBetAmount}
                                 onChange={(e) => setSettings({ ...settings, maxBetAmount: +e.target.value })}
                             />
@@ -54,6 +152,7 @@ export default function Settings() {
                             <label>最小投注额</label>
                             <input
                                 type="number"
+                                step="0.01"
                                 value={settings.minBetAmount}
                                 onChange={(e) => setSettings({ ...settings, minBetAmount: +e.target.value })}
                             />
@@ -108,35 +207,38 @@ export default function Settings() {
                                 <tr>
                                     <th>玩法</th>
                                     <th>当前赔率</th>
-                                    <th>操作</th>
+                                    <th>修改</th>
                                 </tr>
                             </thead>
                             <tbody>
-                                <tr>
-                                    <td>大 (14-27)</td>
-                                    <td>1.95</td>
-                                    <td><button className="btn-edit">编辑</button></td>
-                                </tr>
-                                <tr>
-                                    <td>小 (0-13)</td>
-                                    <td>1.95</td>
-                                    <td><button className="btn-edit">编辑</button></td>
-                                </tr>
-                                <tr>
-                                    <td>单</td>
-                                    <td>1.95</td>
-                                    <td><button className="btn-edit">编辑</button></td>
-                                </tr>
-                                <tr>
-                                    <td>双</td>
-                                    <td>1.95</td>
-                                    <td><button className="btn-edit">编辑</button></td>
-                                </tr>
-                                <tr>
-                                    <td>数字 (0-27)</td>
-                                    <td>9.8</td>
-                                    <td><button className="btn-edit">编辑</button></td>
-                                </tr>
+                                {Object.keys(odds).map((betType) => (
+                                    <tr key={betType}>
+                                        <td>{betTypeLabels[betType] || betType}</td>
+                                        <td>{odds[betType]}</td>
+                                        <td>
+                                            <input
+                                                type="number"
+                                                step="0.01"
+                                                value={odds[betType]}
+                                                onChange={(e) => setOdds({ ...odds, [betType]: +e.target.value })}
+                                            />
+                                        </td>
+                                    </tr>
+                                ))}
+                                {Object.keys(numberOdds).map((sum) => (
+                                    <tr key={`number-${sum}`}>
+                                        <td>数字 {sum}</td>
+                                        <td>{numberOdds[sum]}</td>
+                                        <td>
+                                            <input
+                                                type="number"
+                                                step="0.1"
+                                                value={numberOdds[sum]}
+                                                onChange={(e) => setNumberOdds({ ...numberOdds, [sum]: +e.target.value })}
+                                            />
+                                        </td>
+                                    </tr>
+                                ))}
                             </tbody>
                         </table>
                     </div>
@@ -144,7 +246,7 @@ export default function Settings() {
             </div>
 
             <div className="settings-footer">
-                <button className="btn btn-primary" onClick={handleSave}>
+                <button className="btn btn-primary" onClick={handleSave} disabled={saveMutation.isPending}>
                     保存设置
                 </button>
             </div>
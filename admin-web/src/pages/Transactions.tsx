@@ -0,0 +1,143 @@
+import { useState } from 'react';
+import { useQuery } from '@tanstack/react-query';
+import { walletApi, type TransactionQuery, type WalletTransaction } from '../api/client';
+import './Users.css';
+import './Transactions.css';
+
+const typeLabels: Record<string, string> = {
+    opening: '期初余额',
+    signup_bonus: '注册赠送',
+    bet: '下注',
+    bet_cancel: '撤单退款',
+    payout: '派彩',
+    refund: '作废退款',
+    correction: '更正差额',
+    chase_reserve: '追号冻结',
+    chase_refund: '追号退回',
+    deposit_request: '充值申请',
+    deposit: '充值',
+    deposit_rejected: '充值拒绝',
+    withdrawal: '提现冻结',
+    withdrawal_paid: '提现出款',
+    withdrawal_refund: '提现退回',
+    adjustment: '人工调账',
+    commission: '推荐佣金',
+};
+
+export default function Transactions() {
+    const [filters, setFilters] = useState<TransactionQuery>({});
+    const [query, setQuery] = useState<TransactionQuery>({ page: 1 });
+
+    const { data, isLoading } = useQuery({
+        queryKey: ['transactions', query],
+        queryFn: async () => {
+            const res = await walletApi.transactions(query);
+            return res.data;
+        },
+    });
+
+    const page = data?.page || 1;
+    const pages = data ? Math.max(1, Math.ceil(data.total / data.page_size)) : 1;
+
+    return (
+        <div className="users-page">
+            <div className="page-header">
+                <h1 className="page-title">账变记录</h1>
+            </div>
+
+            <div className="search-bar transactions-filters">
+                <input
+                    className="search-input"
+                    placeholder="用户名"
+                    value={filters.username || ''}
+                    onChange={(e) => setFilters({ ...filters, username: e.target.value })}
+                />
+                <select
+                    className="search-input"
+                    value={filters.type || ''}
+                    onChange={(e) => setFilters({ ...filters, type: e.target.value })}
+                >
+                    <option value="">全部类型</option>
+                    {Object.entries(typeLabels).map(([value, label]) => (
+                        <option key={value} value={value}>{label}</option>
+                    ))}
+                </select>
+                <input
+                    className="search-input"
+                    placeholder="轮次 ID"
+                    value={filters.round_id || ''}
+                    onChange={(e) => setFilters({ ...filters, round_id: e.target.value })}
+                />
+                <input
+                    className="search-input"
+                    type="date"
+                    value={filters.start_date || ''}
+                    onChange={(e) => setFilters({ ...filters, start_date: e.target.value })}
+                />
+                <input
+                    className="search-input"
+                    type="date"
+                    value={filters.end_date || ''}
+                    onChange={(e) => setFilters({ ...filters, end_date: e.target.value })}
+                />
+                <button className="btn btn-primary" onClick={() => setQuery({ ...filters, page: 1 })}>
+                    搜索
+                </button>
+            </div>
+
+            {isLoading ? (
+                <div className="loading">加载中...</div>
+            ) : !data || data.data.length === 0 ? (
+                <div className="empty">
+                    <span className="empty-icon">💰</span>
+                    <p>暂无账变记录</p>
+                </div>
+            ) : (
+                <div className="users-table-wrapper">
+                    <table className="users-table">
+                        <thead>
+                            <tr>
+                                <th>时间</th>
+                                <th>用户名</th>
+                                <th>类型</th>
+                                <th>金额</th>
+                                <th>变动后余额</th>
+                                <th>关联</th>
+                                <th>备注</th>
+                            </tr>
+                        </thead>
+                        <tbody>
+                            {data.data.map((tx: WalletTransaction) => (
+                                <tr key={tx.ID}>
+                                    <td>{new Date(tx.CreatedAt).toLocaleString()}</td>
+                                    <td className="username">{tx.username}</td>
+                                    <td>{typeLabels[tx.type] || tx.type}</td>
+                                    <td className="balance">{tx.amount > 0 ? '+' : ''}{tx.amount.toLocaleString()}</td>
+                                    <td>¥{tx.balance_after.toLocaleString()}</td>
+                                    <td>
+                                        {tx.bet_id ? `注单 #${tx.bet_id} ` : ''}
+                                        {tx.round_id ? `轮次 #${tx.round_id}` : ''}
+                                        {tx.ref_type ? `${tx.ref_type} #${tx.ref_id}` : ''}
+                                    </td>
+                                    <td>{tx.note || <span className="no-data">-</span>}</td>
+                                </tr>
+                            ))}
+                        </tbody>
+                    </table>
+                </div>
+            )}
+
+            {data && pages > 1 && (
+                <div className="pagination">
+                    <button className="btn btn-secondary" disabled={page <= 1} onClick={() => setQuery({ ...query, page: page - 1 })}>
+                        上一页
+                    </button>
+                    <span>{page} / {pages}</span>
+                    <button className="btn btn-secondary" disabled={page >= pages} onClick={() => setQuery({ ...query, page: page + 1 })}>
+                        下一页
+                    </button>
+                </div>
+            )}
+        </div>
+    );
+}
@@ -0,0 +1,98 @@
+// Command kenostub runs a local fake Keno provider for offline testing.
+//
+// It speaks the same protocol as service.HTTPKenoSource:
+//
+//	GET /keno?issue=<provider issue>  ->  {"issue": "...", "numbers": [20 ints]}
+//
+// Draws are generated once per issue and cached, so repeated requests return
+// the same numbers. Use -delay to simulate a provider that publishes late
+// (404 until the delay has passed) and -fail-rate to inject 5xx errors.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"pcgame/backend/internal/service"
+)
+
+type stub struct {
+	gameSvc   *service.GameService
+	apiKey    string
+	delay     time.Duration
+	failRate  float64
+	mu        sync.Mutex
+	firstSeen map[string]time.Time
+	draws     map[string][]int
+}
+
+func (s *stub) handleDraw(w http.ResponseWriter, r *http.Request) {
+	if s.apiKey != "" && r.Header.Get("X-API-Key") != s.apiKey {
+		http.Error(w, "invalid api key", http.StatusUnauthorized)
+		return
+	}
+
+	issue := r.URL.Query().Get("issue")
+	if issue == "" {
+		http.Error(w, "missing issue", http.StatusBadRequest)
+		return
+	}
+
+	if s.failRate > 0 && rand.Float64() < s.failRate {
+		http.Error(w, "injected failure", http.StatusServiceUnavailable)
+		return
+	}
+
+	s.mu.Lock()
+	first, ok := s.firstSeen[issue]
+	if !ok {
+		first = time.Now()
+		s.firstSeen[issue] = first
+	}
+	if time.Since(first) < s.delay {
+		s.mu.Unlock()
+		http.Error(w, "draw not ready", http.StatusNotFound)
+		return
+	}
+	numbers, ok := s.draws[issue]
+	if !ok {
+		numbers = s.gameSvc.GenerateMockKenoData()
+		s.draws[issue] = numbers
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(service.KenoDrawResponse{
+		Issue:   issue,
+		Numbers: numbers,
+	})
+}
+
+func main() {
+	addr := flag.String("addr", ":9090", "listen address")
+	apiKey := flag.String("api-key", "", "required X-API-Key header (empty disables the check)")
+	delay := flag.Duration("delay", 0, "how long after the first request a draw becomes available")
+	failRate := flag.Float64("fail-rate", 0, "fraction of requests answered with 503 (0-1)")
+	flag.Parse()
+
+	s := &stub{
+		gameSvc:   service.NewGameService(),
+		apiKey:    *apiKey,
+		delay:     *delay,
+		failRate:  *failRate,
+		firstSeen: make(map[string]time.Time),
+		draws:     make(map[string][]int),
+	}
+
+	http.HandleFunc("/keno", s.handleDraw)
+
+	log.Printf("Keno stub listening on %s (delay=%s, fail-rate=%.2f)", *addr, *delay, *failRate)
+	if err := http.ListenAndServe(*addr, nil); err != nil {
+		log.Fatalf("Failed to start keno stub: %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"pcgame/backend/internal/api"
 	"pcgame/backend/internal/config"
 	"pcgame/backend/internal/model"
+	"pcgame/backend/internal/service"
 	"pcgame/backend/internal/tasks"
 	"pcgame/backend/internal/websocket"
 
@@ -24,6 +25,11 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Rounds drawn from the Keno provider must keep pace with its draws
+	if !cfg.Game.UseMockData && cfg.Keno.APIURL != "" {
+		service.SetProviderDrawInterval(cfg.Keno.DrawInterval)
+	}
+
 	// Initialize database
 	db, err := model.InitDB(cfg)
 	if err != nil {
@@ -35,6 +41,16 @@ func main() {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
+	// Ensure the default room exists and owns pre-room rounds
+	if _, err := service.NewRoomService(db).EnsureDefaultRoom(); err != nil {
+		log.Fatalf("Failed to create default room: %v", err)
+	}
+
+	// Open the wallet ledger of players whose balance predates it
+	if _, err := service.EnsureOpeningBalances(db); err != nil {
+		log.Fatalf("Failed to open wallet ledger: %v", err)
+	}
+
 	// Initialize WebSocket hub
 	hub := websocket.NewHub()
 	go hub.Run()
@@ -43,10 +59,10 @@ func main() {
 	r := gin.Default()
 
 	// Setup routes
-	api.SetupRoutes(r, db, hub, sugar)
+	api.SetupRoutes(r, db, hub, sugar, cfg)
 
 	// Start scheduler
-	scheduler := tasks.NewScheduler(db, hub, sugar)
+	scheduler := tasks.NewScheduler(db, hub, sugar, cfg)
 	scheduler.Start()
 	defer scheduler.Stop()
 
@@ -0,0 +1,245 @@
+// Command simulate backtests the house edge over synthetic rounds.
+//
+// Each round draws mock Keno data, derives the PC28 result and settles the
+// bets of every player strategy with the real odds and CheckWin logic:
+//
+//	go run ./cmd/simulate -rounds 100000 -strategies flat_big,number_spread
+//	go run ./cmd/simulate -odds proposed.json   # try new odds before saving them
+//
+// The odds file uses the same shape as the admin settings API
+// ({"odds": {...}, "number_odds": {...}}); missing entries keep their defaults.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"pcgame/backend/internal/service"
+)
+
+// bet is one wager placed by a strategy
+type bet struct {
+	betType  string
+	betValue int
+	amount   float64
+}
+
+// strategy decides the bets for a round given the player's previous result
+type strategy struct {
+	name  string
+	desc  string
+	place func(round int, lastWon bool, lastStake float64) []bet
+}
+
+func strategies(stake, maxBet float64) []strategy {
+	return []strategy{
+		{"flat_big", "flat stake on big every round", func(int, bool, float64) []bet {
+			return []bet{{"big", 0, stake}}
+		}},
+		{"flat_small", "flat stake on small every round", func(int, bool, float64) []bet {
+			return []bet{{"small", 0, stake}}
+		}},
+		{"alternate_big_small", "alternate big and small", func(round int, _ bool, _ float64) []bet {
+			if round%2 == 0 {
+				return []bet{{"big", 0, stake}}
+			}
+			return []bet{{"small", 0, stake}}
+		}},
+		{"flat_odd", "flat stake on odd every round", func(int, bool, float64) []bet {
+			return []bet{{"odd", 0, stake}}
+		}},
+		{"combo_spread", "flat stake on all four big/small odd/even combos", func(int, bool, float64) []bet {
+			return []bet{
+				{"big_odd", 0, stake}, {"big_even", 0, stake},
+				{"small_odd", 0, stake}, {"small_even", 0, stake},
+			}
+		}},
+		{"number_spread", "flat stake on each sum 10-17", func(int, bool, float64) []bet {
+			bets := make([]bet, 0, 8)
+			for sum := 10; sum <= 17; sum++ {
+				bets = append(bets, bet{"number", sum, stake})
+			}
+			return bets
+		}},
+		{"extremes", "flat stake on extreme_big and extreme_small", func(int, bool, float64) []bet {
+			return []bet{{"extreme_big", 0, stake}, {"extreme_small", 0, stake}}
+		}},
+		{"leopard_pair", "flat stake on leopard and pair", func(int, bool, float64) []bet {
+			return []bet{{"leopard", 0, stake}, {"pair", 0, stake}}
+		}},
+		{"martingale_big", "double the big stake after a loss, reset after a win", func(_ int, lastWon bool, lastStake float64) []bet {
+			next := stake
+			if !lastWon && lastStake > 0 {
+				next = math.Min(lastStake*2, maxBet)
+			}
+			return []bet{{"big", 0, next}}
+		}},
+	}
+}
+
+// stats accumulates the house result of one strategy
+type stats struct {
+	rounds     int
+	stake      float64
+	payout     float64
+	sumPnL     float64 // house P&L per round
+	sumPnL2    float64
+	cumulative float64
+	peak       float64
+	drawdown   float64 // worst peak-to-trough fall of cumulative house P&L
+	lastWon    bool
+	lastStake  float64
+}
+
+func (st *stats) record(stake, payout float64) {
+	pnl := stake - payout
+	st.rounds++
+	st.stake += stake
+	st.payout += payout
+	st.sumPnL += pnl
+	st.sumPnL2 += pnl * pnl
+	st.cumulative += pnl
+	if st.cumulative > st.peak {
+		st.peak = st.cumulative
+	}
+	if dd := st.peak - st.cumulative; dd > st.drawdown {
+		st.drawdown = dd
+	}
+}
+
+func (st *stats) variance() float64 {
+	if st.rounds < 2 {
+		return 0
+	}
+	n := float64(st.rounds)
+	mean := st.sumPnL / n
+	return (st.sumPnL2 - n*mean*mean) / (n - 1)
+}
+
+func loadSettings(gameSvc *service.GameService, path string) (*service.GameSettings, error) {
+	settings := gameSvc.DefaultGameSettings()
+	if path == "" {
+		return settings, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var override struct {
+		Odds       map[string]float64 `json:"odds"`
+		NumberOdds map[int]float64    `json:"number_odds"`
+	}
+	if err := json.Unmarshal(data, &override); err != nil {
+		return nil, err
+	}
+	for betType, odds := range override.Odds {
+		if _, ok := settings.Odds[betType]; !ok {
+			return nil, fmt.Errorf("unknown bet type %s", betType)
+		}
+		settings.Odds[betType] = odds
+	}
+	for sum, odds := range override.NumberOdds {
+		if _, ok := settings.NumberOdds[sum]; !ok {
+			return nil, fmt.Errorf("number odds must be for sums 0-27, got %d", sum)
+		}
+		settings.NumberOdds[sum] = odds
+	}
+	return settings, settings.Validate()
+}
+
+func main() {
+	rounds := flag.Int("rounds", 100000, "number of synthetic rounds")
+	stake := flag.Float64("stake", 10, "base stake per bet")
+	names := flag.String("strategies", "", "comma-separated strategies to run (default: all)")
+	oddsFile := flag.String("odds", "", "JSON file with odds overrides")
+	list := flag.Bool("list", false, "list available strategies and exit")
+	flag.Parse()
+
+	gameSvc := service.NewGameService()
+	settings, err := loadSettings(gameSvc, *oddsFile)
+	if err != nil {
+		log.Fatalf("Failed to load odds: %v", err)
+	}
+
+	all := strategies(*stake, settings.MaxBet.Float())
+	if *list {
+		for _, s := range all {
+			fmt.Printf("%-20s %s\n", s.name, s.desc)
+		}
+		return
+	}
+
+	selected := all
+	if *names != "" {
+		selected = nil
+		for _, name := range strings.Split(*names, ",") {
+			found := false
+			for _, s := range all {
+				if s.name == strings.TrimSpace(name) {
+					selected = append(selected, s)
+					found = true
+				}
+			}
+			if !found {
+				log.Fatalf("Unknown strategy %q (use -list)", name)
+			}
+		}
+	}
+
+	results := make([]stats, len(selected))
+	var sumCounts [28]int
+
+	for round := 0; round < *rounds; round++ {
+		result := gameSvc.CalculateResult(gameSvc.GenerateMockKenoData())
+		sumCounts[result.Sum]++
+
+		for i, s := range selected {
+			st := &results[i]
+			var roundStake, roundPayout float64
+			won := false
+			for _, b := range s.place(round, st.lastWon, st.lastStake) {
+				roundStake += b.amount
+				if gameSvc.CheckWin(b.betType, b.betValue, result) {
+					roundPayout += b.amount * settings.BetOdds(b.betType, b.betValue)
+					won = true
+				}
+			}
+			st.record(roundStake, roundPayout)
+			st.lastWon = won
+			st.lastStake = roundStake
+		}
+	}
+
+	fmt.Printf("Simulated %d rounds\n\n", *rounds)
+	fmt.Printf("%-20s %14s %14s %14s %8s %12s %14s\n",
+		"strategy", "stake", "payout", "house P&L", "edge", "std/round", "worst drawdown")
+	for i, s := range selected {
+		st := results[i]
+		edge := 0.0
+		if st.stake > 0 {
+			edge = (st.stake - st.payout) / st.stake
+		}
+		fmt.Printf("%-20s %14.2f %14.2f %14.2f %7.2f%% %12.2f %14.2f\n",
+			s.name, st.stake, st.payout, st.stake-st.payout, edge*100,
+			math.Sqrt(st.variance()), st.drawdown)
+	}
+
+	exact := gameSvc.ExactOutcomeCounts().SumProbabilities()
+	fmt.Printf("\nSum distribution\n%4s %10s %10s %10s\n", "sum", "count", "observed", "exact")
+	sums := make([]int, 0, 28)
+	for sum := range sumCounts {
+		sums = append(sums, sum)
+	}
+	sort.Ints(sums)
+	for _, sum := range sums {
+		fmt.Printf("%4d %10d %9.4f%% %9.4f%%\n", sum, sumCounts[sum],
+			float64(sumCounts[sum])/float64(*rounds)*100, exact[sum]*100)
+	}
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"errors"
+
+	"pcgame/backend/internal/model"
+	"pcgame/backend/internal/service"
+	ws "pcgame/backend/internal/websocket"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AdjustmentHandler handles manual balance adjustments (人工调账)
+type AdjustmentHandler struct {
+	db            *gorm.DB
+	hub           *ws.Hub
+	adjustmentSvc *service.AdjustmentService
+}
+
+// NewAdjustmentHandler creates a new adjustment handler
+func NewAdjustmentHandler(db *gorm.DB, hub *ws.Hub, approvalThreshold model.Money) *AdjustmentHandler {
+	return &AdjustmentHandler{
+		db:            db,
+		hub:           hub,
+		adjustmentSvc: service.NewAdjustmentService(db, approvalThreshold),
+	}
+}
+
+// SetupAdjustmentRoutes sets up admin balance adjustment routes
+func SetupAdjustmentRoutes(r *gin.RouterGroup, db *gorm.DB, hub *ws.Hub, approvalThreshold model.Money) {
+	h := NewAdjustmentHandler(db, hub, approvalThreshold)
+
+	adjustments := r.Group("/adjustments")
+	adjustments.Use(RequireRole(model.RoleSuperAdmin, model.RoleAdmin))
+	{
+		adjustments.GET("", h.List)
+		adjustments.POST("", h.Create)
+		adjustments.POST("/:id/approve", h.Approve)
+		adjustments.POST("/:id/reject", h.Reject)
+	}
+}
+
+// CreateAdjustmentRequest represents a manual balance adjustment
+type CreateAdjustmentRequest struct {
+	UserID uint                   `json:"user_id" binding:"required,gt=0"`
+	Amount model.Money            `json:"amount" binding:"required"` // Signed: negative debits
+	Reason model.AdjustmentReason `json:"reason" binding:"required,max=20"`
+	Note   string                 `json:"note" binding:"required,max=255"`
+}
+
+// Create adjusts a player's balance, or queues the adjustment for a second
+// admin when it exceeds the approval threshold
+func (h *AdjustmentHandler) Create(c *gin.Context) {
+	var req CreateAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	users, ok := userScope(c, h.db)
+	if !ok {
+		c.JSON(403, gin.H{"error": "Access denied"})
+		return
+	}
+	var count int64
+	h.db.Model(&model.User{}).Where("id = ? AND id IN (?)", req.UserID, users).Count(&count)
+	if count == 0 {
+		c.JSON(404, gin.H{"error": "User not found"})
+		return
+	}
+
+	adminID, _ := c.Get("admin_id")
+
+	res, err := h.adjustmentSvc.Create(adminID.(uint), service.AdjustmentRequest{
+		UserID: req.UserID,
+		Amount: req.Amount,
+		Reason: req.Reason,
+		Note:   req.Note,
+	})
+	if err != nil {
+		respondAdjustmentError(c, err, "Failed to adjust balance")
+		return
+	}
+
+	h.notify(res)
+	c.JSON(201, gin.H{"adjustment": res.Adjustment, "balance": res.Balance})
+}
+
+// adjustmentRow is an adjustment with its player's username
+type adjustmentRow struct {
+	model.BalanceAdjustment
+	Username string `json:"username"`
+}
+
+// List searches the adjustments of the players the admin may see, newest first
+// Query: status, reason, user_id, username, page, page_size
+func (h *AdjustmentHandler) List(c *gin.Context) {
+	users, ok := userScope(c, h.db)
+	if !ok {
+		c.JSON(403, gin.H{"error": "Access denied"})
+		return
+	}
+
+	query := h.db.Model(&model.BalanceAdjustment{}).
+		Joins("JOIN users ON users.id = balance_adjustments.user_id").
+		Where("balance_adjustments.user_id IN (?)", users)
+
+	if v := c.Query("status"); v != "" {
+		query = query.Where("balance_adjustments.status = ?", v)
+	}
+	if v := c.Query("reason"); v != "" {
+		query = query.Where("balance_adjustments.reason = ?", v)
+	}
+	if v := c.Query("user_id"); v != "" {
+		query = query.Where("balance_adjustments.user_id = ?", v)
+	}
+	if v := c.Query("username"); v != "" {
+		query = query.Where("users.username = ?", v)
+	}
+
+	page, size := pageParams(c)
+
+	var total int64
+	query.Count(&total)
+
+	rows := make([]adjustmentRow, 0)
+	query.Select("balance_adjustments.*, users.username").
+		Order("balance_adjustments.id desc").
+		Offset((page - 1) * size).
+		Limit(size).
+		Scan(&rows)
+
+	c.JSON(200, gin.H{"data": rows, "total": total, "page": page, "page_size": size})
+}
+
+// ReviewAdjustmentRequest represents an approve or reject decision
+type ReviewAdjustmentRequest struct {
+	Note string `json:"note" binding:"max=255"`
+}
+
+// Approve applies a pending adjustment; the requester cannot approve their own
+func (h *AdjustmentHandler) Approve(c *gin.Context) {
+	h.review(c, h.adjustmentSvc.Approve)
+}
+
+// Reject declines a pending adjustment
+func (h *AdjustmentHandler) Reject(c *gin.Context) {
+	h.review(c, h.adjustmentSvc.Reject)
+}
+
+func (h *AdjustmentHandler) review(c *gin.Context, decide func(id, adminID uint, note string) (*service.AdjustmentResult, error)) {
+	id, err := ValidateID(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req ReviewAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	users, ok := userScope(c, h.db)
+	if !ok {
+		c.JSON(403, gin.H{"error": "Access denied"})
+		return
+	}
+	var count int64
+	h.db.Model(&model.BalanceAdjustment{}).Where("id = ? AND user_id IN (?)", id, users).Count(&count)
+	if count == 0 {
+		c.JSON(404, gin.H{"error": "Adjustment not found"})
+		return
+	}
+
+	adminID, _ := c.Get("admin_id")
+
+	res, err := decide(id, adminID.(uint), req.Note)
+	if err != nil {
+		respondAdjustmentError(c, err, "Failed to review adjustment")
+		return
+	}
+
+	h.notify(res)
+	c.JSON(200, res.Adjustment)
+}
+
+// notify pushes the new balance to the player once an adjustment is applied
+func (h *AdjustmentHandler) notify(res *service.AdjustmentResult) {
+	if res.Adjustment.Status == model.AdjustmentStatusApplied {
+		h.hub.SendBalance(res.Adjustment.UserID, res.Balance, "adjustment")
+	}
+}
+
+// respondAdjustmentError maps adjustment service errors to responses
+func respondAdjustmentError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrAdjustmentNotFound):
+		c.JSON(404, gin.H{"error": "Adjustment not found"})
+	case errors.Is(err, service.ErrAdjustmentNotPending):
+		c.JSON(400, gin.H{"error": "Adjustment has already been reviewed"})
+	case errors.Is(err, service.ErrSelfApproval):
+		c.JSON(403, gin.H{"error": "Adjustment must be approved by a different admin"})
+	case errors.Is(err, service.ErrInsufficientBalance):
+		c.JSON(400, gin.H{"error": "Insufficient balance"})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(404, gin.H{"error": "User not found"})
+	case errors.Is(err, service.ErrInvalidAdjustment):
+		c.JSON(400, gin.H{"error": err.Error()})
+	default:
+		c.JSON(500, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"errors"
+
+	"pcgame/backend/internal/model"
+	"pcgame/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ChaseHandler handles chase plans (追号)
+type ChaseHandler struct {
+	db       *gorm.DB
+	chaseSvc *service.ChaseService
+}
+
+// NewChaseHandler creates a new chase handler
+func NewChaseHandler(db *gorm.DB) *ChaseHandler {
+	return &ChaseHandler{
+		db:       db,
+		chaseSvc: service.NewChaseService(db),
+	}
+}
+
+// SetupChaseRoutes sets up chase plan routes on the player bets group
+func SetupChaseRoutes(bets *gin.RouterGroup, db *gorm.DB) {
+	h := NewChaseHandler(db)
+
+	chase := bets.Group("/chase")
+	{
+		chase.POST("", h.Create)
+		chase.GET("", h.List)
+		chase.GET("/:id", h.Get)
+		chase.DELETE("/:id", h.Cancel)
+	}
+}
+
+// ChaseRequest represents a chase plan request
+type ChaseRequest struct {
+	Room       string      `json:"room"` // Optional; defaults to the default room
+	BetType    string      `json:"bet_type" binding:"required,max=20"`
+	BetValue   int         `json:"bet_value" binding:"min=0"`
+	Amount     model.Money `json:"amount" binding:"required,gt=0"`              // Stake of the first round
+	Rounds     int         `json:"rounds" binding:"required,min=1"`             // Number of upcoming rounds
+	Multiplier float64     `json:"multiplier" binding:"omitempty,min=1,max=10"` // Stake factor per round; 1 when omitted
+	StopOnWin  bool        `json:"stop_on_win"`
+}
+
+// Create creates a chase plan and reserves its stakes
+func (h *ChaseHandler) Create(c *gin.Context) {
+	var req ChaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := GetUserIDFromContext(c)
+	if !ok {
+		c.JSON(401, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	plan, err := h.chaseSvc.Create(userID, service.ChaseRequest{
+		Room:       req.Room,
+		BetType:    req.BetType,
+		BetValue:   req.BetValue,
+		Amount:     req.Amount,
+		Rounds:     req.Rounds,
+		Multiplier: req.Multiplier,
+		StopOnWin:  req.StopOnWin,
+	})
+	if err != nil {
+		respondBetError(c, err, "Failed to create chase plan")
+		return
+	}
+	c.JSON(201, plan)
+}
+
+// List returns the player's latest chase plans
+// Query: status (optional)
+func (h *ChaseHandler) List(c *gin.Context) {
+	userID, ok := GetUserIDFromContext(c)
+	if !ok {
+		c.JSON(401, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	query := h.db.Where("user_id = ?", userID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var plans []model.ChasePlan
+	query.Order("id desc").
+		Limit(50).
+		Find(&plans)
+	c.JSON(200, plans)
+}
+
+// Get returns one of the player's chase plans with the bets it placed
+func (h *ChaseHandler) Get(c *gin.Context) {
+	userID, ok := GetUserIDFromContext(c)
+	if !ok {
+		c.JSON(401, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	id, err := ValidateID(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid chase plan ID"})
+		return
+	}
+
+	var plan model.ChasePlan
+	if err := h.db.Where("id = ? AND user_id = ?", id, userID).First(&plan).Error; err != nil {
+		c.JSON(404, gin.H{"error": "Chase plan not found"})
+		return
+	}
+
+	var bets []model.PC28Bet
+	h.db.Where("chase_plan_id = ?", plan.ID).Preload("Round").Order("id asc").Find(&bets)
+
+	type betWithRound struct {
+		model.PC28Bet
+		IssueNumber string `json:"issue_number"`
+	}
+	items := make([]betWithRound, len(bets))
+	for i, bet := range bets {
+		items[i] = betWithRound{PC28Bet: bet, IssueNumber: bet.Round.IssueNumber}
+	}
+
+	c.JSON(200, gin.H{
+		"plan":       plan,
+		"bets":       items,
+		"next_stake": service.ChaseStake(plan.BaseAmount, plan.Multiplier, plan.PlacedRounds),
+	})
+}
+
+// Cancel cancels an active chase plan and refunds its unplaced stakes
+func (h *ChaseHandler) Cancel(c *gin.Context) {
+	userID, ok := GetUserIDFromContext(c)
+	if !ok {
+		c.JSON(401, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	id, err := ValidateID(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid chase plan ID"})
+		return
+	}
+
+	plan, err := h.chaseSvc.Cancel(userID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrChaseNotFound):
+			c.JSON(404, gin.H{"error": "Chase plan not found"})
+		case errors.Is(err, service.ErrChaseNotActive):
+			c.JSON(400, gin.H{"error": "Chase plan is not active"})
+		default:
+			c.JSON(500, gin.H{"error": "Failed to cancel chase plan"})
+		}
+		return
+	}
+	c.JSON(200, plan)
+}
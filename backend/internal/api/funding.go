@@ -0,0 +1,248 @@
+package api
+
+import (
+	"errors"
+
+	"pcgame/backend/internal/model"
+	"pcgame/backend/internal/service"
+	ws "pcgame/backend/internal/websocket"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// FundingHandler handles deposit and withdrawal requests (充值/提现)
+type FundingHandler struct {
+	db         *gorm.DB
+	hub        *ws.Hub
+	fundingSvc *service.FundingService
+}
+
+// NewFundingHandler creates a new funding handler
+func NewFundingHandler(db *gorm.DB, hub *ws.Hub) *FundingHandler {
+	return &FundingHandler{
+		db:         db,
+		hub:        hub,
+		fundingSvc: service.NewFundingService(db),
+	}
+}
+
+// SetupPlayerFundingRoutes sets up funding routes on the player group
+func SetupPlayerFundingRoutes(player *gin.RouterGroup, db *gorm.DB, hub *ws.Hub) {
+	h := NewFundingHandler(db, hub)
+
+	player.POST("/deposits", h.Deposit)
+	player.POST("/withdrawals", h.Withdraw)
+	player.GET("/funding", h.PlayerList)
+}
+
+// SetupFundingRoutes sets up the admin review of funding requests
+func SetupFundingRoutes(r *gin.RouterGroup, db *gorm.DB, hub *ws.Hub) {
+	h := NewFundingHandler(db, hub)
+
+	funding := r.Group("/funding")
+	funding.Use(RequireRole(model.RoleSuperAdmin, model.RoleAdmin))
+	{
+		funding.GET("", h.List)
+		funding.POST("/:id/approve", h.Approve)
+		funding.POST("/:id/reject", h.Reject)
+	}
+}
+
+// FundingRequest represents a deposit or withdrawal request
+type FundingRequest struct {
+	Amount  model.Money `json:"amount" binding:"required,gt=0"`
+	Method  string      `json:"method" binding:"required,max=20"`
+	Account string      `json:"account" binding:"required,max=100"` // Payer reference or payee account
+	Note    string      `json:"note" binding:"max=255"`
+}
+
+// Deposit submits a deposit request; the balance is credited once approved
+func (h *FundingHandler) Deposit(c *gin.Context) {
+	h.submit(c, model.FundingDeposit)
+}
+
+// Withdraw submits a withdrawal request and holds its amount from the balance
+func (h *FundingHandler) Withdraw(c *gin.Context) {
+	h.submit(c, model.FundingWithdrawal)
+}
+
+func (h *FundingHandler) submit(c *gin.Context, fundingType model.FundingType) {
+	var req FundingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := GetUserIDFromContext(c)
+	if !ok {
+		c.JSON(401, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	res, err := h.fundingSvc.Submit(userID, service.FundingSubmission{
+		Type:    fundingType,
+		Amount:  req.Amount,
+		Method:  req.Method,
+		Account: req.Account,
+		Note:    req.Note,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInsufficientBalance):
+			c.JSON(400, gin.H{"error": "Insufficient balance"})
+		case errors.Is(err, service.ErrInvalidFunding):
+			c.JSON(400, gin.H{"error": err.Error()})
+		default:
+			c.JSON(500, gin.H{"error": "Failed to submit request"})
+		}
+		return
+	}
+
+	h.notify(res)
+	c.JSON(201, gin.H{"request": res.Request, "balance": res.Balance})
+}
+
+// filterFunding applies the filters shared by players and admins
+// Query: type, status
+func filterFunding(c *gin.Context, query *gorm.DB) *gorm.DB {
+	if t := c.Query("type"); t != "" {
+		query = query.Where("funding_requests.type = ?", t)
+	}
+	if s := c.Query("status"); s != "" {
+		query = query.Where("funding_requests.status = ?", s)
+	}
+	return query
+}
+
+// PlayerList returns the current player's funding requests, newest first
+// Query: type, status, page, page_size
+func (h *FundingHandler) PlayerList(c *gin.Context) {
+	userID, ok := GetUserIDFromContext(c)
+	if !ok {
+		c.JSON(401, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	page, size := pageParams(c)
+	query := filterFunding(c, h.db.Model(&model.FundingRequest{}).Where("user_id = ?", userID))
+
+	var total int64
+	query.Count(&total)
+
+	reqs := make([]model.FundingRequest, 0)
+	query.Order("id desc").Offset((page - 1) * size).Limit(size).Find(&reqs)
+
+	c.JSON(200, gin.H{"data": reqs, "total": total, "page": page, "page_size": size})
+}
+
+// fundingRow is a funding request with its player's username
+type fundingRow struct {
+	model.FundingRequest
+	Username string `json:"username"`
+}
+
+// List searches the funding requests of the players the admin may see,
+// newest first
+// Query: type, status, user_id, username, page, page_size
+func (h *FundingHandler) List(c *gin.Context) {
+	users, ok := userScope(c, h.db)
+	if !ok {
+		c.JSON(403, gin.H{"error": "Access denied"})
+		return
+	}
+
+	query := h.db.Model(&model.FundingRequest{}).
+		Joins("JOIN users ON users.id = funding_requests.user_id").
+		Where("funding_requests.user_id IN (?)", users)
+
+	if v := c.Query("user_id"); v != "" {
+		query = query.Where("funding_requests.user_id = ?", v)
+	}
+	if v := c.Query("username"); v != "" {
+		query = query.Where("users.username = ?", v)
+	}
+	query = filterFunding(c, query)
+
+	page, size := pageParams(c)
+
+	var total int64
+	query.Count(&total)
+
+	rows := make([]fundingRow, 0)
+	query.Select("funding_requests.*, users.username").
+		Order("funding_requests.id desc").
+		Offset((page - 1) * size).
+		Limit(size).
+		Scan(&rows)
+
+	c.JSON(200, gin.H{"data": rows, "total": total, "page": page, "page_size": size})
+}
+
+// ReviewFundingRequest represents an approve or reject decision
+type ReviewFundingRequest struct {
+	Note string `json:"note" binding:"max=255"`
+}
+
+// Approve approves a pending request: a deposit is credited, a withdrawal is paid out
+func (h *FundingHandler) Approve(c *gin.Context) {
+	h.review(c, h.fundingSvc.Approve)
+}
+
+// Reject rejects a pending request: a withdrawal's held amount is returned
+func (h *FundingHandler) Reject(c *gin.Context) {
+	h.review(c, h.fundingSvc.Reject)
+}
+
+func (h *FundingHandler) review(c *gin.Context, decide func(id, adminID uint, note string) (*service.FundingResult, error)) {
+	id, err := ValidateID(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req ReviewFundingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	users, ok := userScope(c, h.db)
+	if !ok {
+		c.JSON(403, gin.H{"error": "Access denied"})
+		return
+	}
+	var count int64
+	h.db.Model(&model.FundingRequest{}).Where("id = ? AND user_id IN (?)", id, users).Count(&count)
+	if count == 0 {
+		c.JSON(404, gin.H{"error": "Funding request not found"})
+		return
+	}
+
+	adminID, _ := c.Get("admin_id")
+
+	res, err := decide(id, adminID.(uint), req.Note)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrFundingNotFound):
+			c.JSON(404, gin.H{"error": "Funding request not found"})
+		case errors.Is(err, service.ErrFundingNotPending):
+			c.JSON(400, gin.H{"error": "Funding request has already been reviewed"})
+		default:
+			c.JSON(500, gin.H{"error": "Failed to review request"})
+		}
+		return
+	}
+
+	h.notify(res)
+	c.JSON(200, res.Request)
+}
+
+// notify pushes a request's new state, and the balance if it moved, to its player
+func (h *FundingHandler) notify(res *service.FundingResult) {
+	req := res.Request
+	h.hub.SendToUser(req.UserID, ws.Message{Type: ws.MsgTypeFunding, Payload: req})
+	if res.BalanceChanged {
+		h.hub.SendBalance(req.UserID, res.Balance, string(req.Type)+"_"+string(req.Status))
+	}
+}
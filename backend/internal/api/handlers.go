@@ -1,8 +1,13 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
+	"pcgame/backend/internal/config"
 	"pcgame/backend/internal/model"
 	"pcgame/backend/internal/service"
 	ws "pcgame/backend/internal/websocket"
@@ -21,26 +26,32 @@ var upgrader = websocket.Upgrader{
 
 // Handler holds dependencies for API handlers
 type Handler struct {
-	db      *gorm.DB
-	hub     *ws.Hub
-	logger  *zap.SugaredLogger
-	gameSvc *service.GameService
+	db          *gorm.DB
+	hub         *ws.Hub
+	logger      *zap.SugaredLogger
+	gameSvc     *service.GameService
+	settingsSvc *service.SettingsService
+	roomSvc     *service.RoomService
+	betSvc      *service.BetService
 }
 
 // NewHandler creates a new handler
 func NewHandler(db *gorm.DB, hub *ws.Hub, logger *zap.SugaredLogger) *Handler {
 	return &Handler{
-		db:      db,
-		hub:     hub,
-		logger:  logger,
-		gameSvc: service.NewGameService(),
+		db:          db,
+		hub:         hub,
+		logger:      logger,
+		gameSvc:     service.NewGameService(),
+		settingsSvc: service.NewSettingsService(db),
+		roomSvc:     service.NewRoomService(db),
+		betSvc:      service.NewBetService(db),
 	}
 }
 
 // SetupRoutes sets up all API routes
-func SetupRoutes(r *gin.Engine, db *gorm.DB, hub *ws.Hub, logger *zap.SugaredLogger) {
+func SetupRoutes(r *gin.Engine, db *gorm.DB, hub *ws.Hub, logger *zap.SugaredLogger, cfg *config.Config) {
 	h := NewHandler(db, hub, logger)
-	userHandler := NewUserHandler(db)
+	userHandler := NewUserHandler(db, hub, cfg.Referral)
 
 	// Security middleware
 	r.Use(SecurityHeadersMiddleware())
@@ -66,11 +77,17 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB, hub *ws.Hub, logger *zap.SugaredLog
 		// ==========================================
 
 		// Game info routes
+		v1.GET("/time", h.GetServerTime)
+		v1.GET("/games", h.GetGames)
+
 		games := v1.Group("/games/pc28")
 		{
+			games.GET("/rooms", h.GetRooms)
 			games.GET("/round/current", h.GetCurrentRound)
 			games.GET("/history", h.GetHistory)
+			games.GET("/schedule", h.GetSchedule)
 			games.GET("/odds", h.GetOdds)
+			games.GET("/verify", h.VerifyRound)
 		}
 
 		// Player auth routes (public, with rate limiting)
@@ -93,9 +110,12 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB, hub *ws.Hub, logger *zap.SugaredLog
 		{
 			bets.POST("", h.PlaceBet)
 			bets.GET("", h.GetUserBets)
+			bets.POST("/slip", h.PlaceBetSlip)
+			bets.DELETE("/:id", h.CancelBet)
+			SetupChaseRoutes(bets, db)
 		}
 
-		SetupUserRoutes(v1, db)
+		SetupUserRoutes(v1, db, hub, cfg.Referral)
 
 		// ==========================================
 		// Admin Protected Routes
@@ -105,6 +125,12 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB, hub *ws.Hub, logger *zap.SugaredLog
 		admin.Use(AuthMiddleware(db))
 		{
 			SetupOperatorRoutes(admin, db)
+			SetupRoundRoutes(admin, db, hub, logger)
+			SetupSettingsRoutes(admin, db)
+			SetupRoomRoutes(admin, db)
+			SetupWalletRoutes(admin, db)
+			SetupFundingRoutes(admin, db, hub)
+			SetupAdjustmentRoutes(admin, db, hub, model.MoneyFromFloat(cfg.Wallet.AdjustmentApprovalThreshold))
 		}
 	}
 
@@ -112,37 +138,218 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB, hub *ws.Hub, logger *zap.SugaredLog
 	r.GET("/ws", h.HandleWebSocket)
 }
 
-// GetCurrentRound returns the current open round
+// queryRoom resolves the ?room= query parameter, defaulting to the default room
+// It writes the error response and returns nil if the room is unknown
+func (h *Handler) queryRoom(c *gin.Context) *model.GameRoom {
+	room, err := h.roomSvc.GetByCode(c.Query("room"))
+	if err != nil {
+		if errors.Is(err, service.ErrRoomNotFound) {
+			c.JSON(404, gin.H{"error": "Room not found"})
+		} else {
+			c.JSON(500, gin.H{"error": "Failed to load room"})
+		}
+		return nil
+	}
+	return room
+}
+
+// GetServerTime returns the server clock so clients can align their countdowns
+// Clients should estimate their offset as server_time_ms - (sent + received) / 2
+func (h *Handler) GetServerTime(c *gin.Context) {
+	now := time.Now()
+	c.JSON(200, gin.H{
+		"server_time":    now.Format(time.RFC3339Nano),
+		"server_time_ms": now.UnixMilli(),
+	})
+}
+
+// GetGames returns the registered games
+func (h *Handler) GetGames(c *gin.Context) {
+	result := make([]gin.H, 0)
+	for _, game := range service.RegisteredGames() {
+		result = append(result, gin.H{"code": game.Code(), "name": game.Name()})
+	}
+	c.JSON(200, result)
+}
+
+// GetRooms returns the active game rooms
+func (h *Handler) GetRooms(c *gin.Context) {
+	rooms, err := h.roomSvc.ListActive()
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load rooms"})
+		return
+	}
+
+	settings, err := h.settingsSvc.Get()
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load settings"})
+		return
+	}
+
+	result := make([]gin.H, 0, len(rooms))
+	for i := range rooms {
+		roomSettings := *settings
+		roomSettings.ApplyRoom(&rooms[i])
+		result = append(result, gin.H{
+			"code":           rooms[i].Code,
+			"name":           rooms[i].Name,
+			"game":           rooms[i].GameCode,
+			"issue_prefix":   rooms[i].IssuePrefix,
+			"round_duration": roomSettings.RoundDuration,
+			"betting_window": roomSettings.BettingWindow,
+			"bet_cutoff":     roomSettings.BetCutoff,
+			"topic":          ws.RoomTopic(rooms[i].Code),
+		})
+	}
+	c.JSON(200, result)
+}
+
+// GetCurrentRound returns the current open round of a room
+// Query: room (optional, defaults to pc28)
 func (h *Handler) GetCurrentRound(c *gin.Context) {
+	room := h.queryRoom(c)
+	if room == nil {
+		return
+	}
+
 	var round model.PC28Round
-	if err := h.db.Where("status = ?", model.RoundStatusOpen).Order("id desc").First(&round).Error; err != nil {
+	if err := h.db.Where("room_id = ? AND status = ?", room.ID, model.RoundStatusOpen).
+		Order("id desc").First(&round).Error; err != nil {
 		c.JSON(404, gin.H{"error": "No open round"})
 		return
 	}
 	c.JSON(200, round)
 }
 
-// GetHistory returns recent game history
+// GetHistory returns recent game history of a room
+// Query: room (optional, defaults to pc28)
 func (h *Handler) GetHistory(c *gin.Context) {
+	room := h.queryRoom(c)
+	if room == nil {
+		return
+	}
+
 	var rounds []model.PC28Round
-	h.db.Where("status = ?", model.RoundStatusSettled).
+	h.db.Where("room_id = ? AND status = ?", room.ID, model.RoundStatusSettled).
 		Order("id desc").
 		Limit(20).
 		Find(&rounds)
 	c.JSON(200, rounds)
 }
 
-// GetOdds returns the current odds
+// GetSchedule returns the current and upcoming rounds of a room
+// Query: room (optional, defaults to pc28)
+func (h *Handler) GetSchedule(c *gin.Context) {
+	room := h.queryRoom(c)
+	if room == nil {
+		return
+	}
+
+	settings, err := h.settingsSvc.GetForRoom(room)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load settings"})
+		return
+	}
+
+	var rounds []model.PC28Round
+	h.db.Where("room_id = ? AND status IN ?", room.ID,
+		[]model.RoundStatus{model.RoundStatusOpen, model.RoundStatusPending}).
+		Order("open_time asc").
+		Limit(50).
+		Find(&rounds)
+
+	result := make([]gin.H, 0, len(rounds))
+	for _, round := range rounds {
+		result = append(result, gin.H{
+			"round_id":         round.ID,
+			"issue_number":     round.IssueNumber,
+			"open_time":        round.OpenTime,
+			"close_time":       round.CloseTime,
+			"bet_deadline":     settings.BetDeadline(round.CloseTime),
+			"status":           round.Status,
+			"server_seed_hash": round.ServerSeedHash,
+		})
+	}
+	c.JSON(200, result)
+}
+
+// GetOdds returns the odds of a room, including the per-sum table for number bets
+// Query: room (optional, defaults to pc28)
 func (h *Handler) GetOdds(c *gin.Context) {
-	c.JSON(200, h.gameSvc.GetOdds())
+	room := h.queryRoom(c)
+	if room == nil {
+		return
+	}
+
+	settings, err := h.settingsSvc.GetForRoom(room)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load odds"})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"odds":        settings.Odds,
+		"number_odds": settings.NumberOdds,
+		"min_bet":     settings.MinBet,
+		"max_bet":     settings.MaxBet,
+		"bet_cutoff":  settings.BetCutoff,
+	})
+}
+
+// VerifyRound recomputes a provably fair draw from its revealed seed
+// Query: issue_number (required), server_seed (optional, defaults to the revealed seed)
+func (h *Handler) VerifyRound(c *gin.Context) {
+	issueNumber := c.Query("issue_number")
+	if issueNumber == "" {
+		c.JSON(400, gin.H{"error": "issue_number is required"})
+		return
+	}
+
+	var round model.PC28Round
+	if err := h.db.Where("issue_number = ?", issueNumber).First(&round).Error; err != nil {
+		c.JSON(404, gin.H{"error": "Round not found"})
+		return
+	}
+
+	if round.ServerSeedHash == "" {
+		c.JSON(400, gin.H{"error": "Round is not provably fair"})
+		return
+	}
+	if round.DrawnAt == nil {
+		c.JSON(400, gin.H{"error": "Server seed not revealed yet"})
+		return
+	}
+
+	_, game, err := h.roomSvc.GameForRound(&round)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load game"})
+		return
+	}
+
+	seed := c.DefaultQuery("server_seed", round.ServerSeed)
+	kenoData := h.gameSvc.GenerateSeededKenoData(seed, round.IssueNumber)
+	result := game.Result(kenoData)
+
+	c.JSON(200, gin.H{
+		"issue_number":     round.IssueNumber,
+		"server_seed":      seed,
+		"server_seed_hash": round.ServerSeedHash,
+		"salt":             round.IssueNumber,
+		"keno_data":        kenoData,
+		"result":           result,
+		"hash_matches":     h.gameSvc.HashServerSeed(seed) == round.ServerSeedHash,
+		"result_matches": result.A == round.ResultA && result.B == round.ResultB &&
+			result.C == round.ResultC && result.Sum == round.Sum,
+	})
 }
 
 // PlaceBetRequest represents a bet placement request
 type PlaceBetRequest struct {
-	RoundID  uint    `json:"round_id" binding:"required,gt=0"`
-	BetType  string  `json:"bet_type" binding:"required,oneof=number big small odd even big_odd big_even small_odd small_even"`
-	BetValue int     `json:"bet_value" binding:"min=0,max=27"`
-	Amount   float64 `json:"amount" binding:"required,gt=0,lte=100000"`
+	RoundID  uint        `json:"round_id" binding:"required,gt=0"`
+	Room     string      `json:"room"`                               // Optional; must match the round's room when set
+	BetType  string      `json:"bet_type" binding:"required,max=20"` // Validated by the room's game
+	BetValue int         `json:"bet_value" binding:"min=0"`
+	Amount   model.Money `json:"amount" binding:"required,gt=0"` // At most two decimals; limits come from game settings
 }
 
 // PlaceBet places a new bet
@@ -159,65 +366,134 @@ func (h *Handler) PlaceBet(c *gin.Context) {
 		return
 	}
 
-	var round model.PC28Round
-	if err := h.db.First(&round, req.RoundID).Error; err != nil {
-		c.JSON(404, gin.H{"error": "Round not found"})
+	bet, err := h.betSvc.Place(userID, service.BetRequest{
+		RoundID:  req.RoundID,
+		Room:     req.Room,
+		BetType:  req.BetType,
+		BetValue: req.BetValue,
+		Amount:   req.Amount,
+	})
+	if err != nil {
+		respondBetError(c, err, "Failed to create bet")
 		return
 	}
+	c.JSON(201, bet)
+}
+
+// PlaceBetSlipRequest represents a bet slip: several bets placed together
+type PlaceBetSlipRequest struct {
+	Room string            `json:"room"` // Optional default for lines without a room
+	Bets []PlaceBetRequest `json:"bets" binding:"required,min=1,dive"`
+}
 
-	if round.Status != model.RoundStatusOpen {
-		c.JSON(400, gin.H{"error": "Round is not accepting bets"})
+// PlaceBetSlip places every bet of a slip in one transaction, or none of them
+func (h *Handler) PlaceBetSlip(c *gin.Context) {
+	var req PlaceBetSlipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
 
-	odds := h.gameSvc.GetOdds()[req.BetType]
-	if odds == 0 {
-		c.JSON(400, gin.H{"error": "Invalid bet type"})
+	userID, ok := GetUserIDFromContext(c)
+	if !ok {
+		c.JSON(401, gin.H{"error": "Not authenticated"})
 		return
 	}
 
-	tx := h.db.Begin()
+	lines := make([]service.BetRequest, len(req.Bets))
+	for i, b := range req.Bets {
+		lines[i] = service.BetRequest{
+			RoundID:  b.RoundID,
+			Room:     b.Room,
+			BetType:  b.BetType,
+			BetValue: b.BetValue,
+			Amount:   b.Amount,
+		}
+		if lines[i].Room == "" {
+			lines[i].Room = req.Room
+		}
+	}
 
-	var user model.User
-	if err := tx.First(&user, userID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(404, gin.H{"error": "User not found"})
+	bets, rejected, err := h.betSvc.PlaceBatch(userID, lines)
+	if err != nil {
+		if errors.Is(err, service.ErrBetSlipRejected) {
+			msgs := make([]string, len(rejected))
+			for i, r := range rejected {
+				msgs[i] = fmt.Sprintf("#%d: %s", r.Index+1, r.Error)
+			}
+			c.JSON(400, gin.H{
+				"error":    "Bet slip rejected: " + strings.Join(msgs, "; "),
+				"rejected": rejected,
+			})
+			return
+		}
+		respondBetError(c, err, "Failed to place bet slip")
 		return
 	}
 
-	if user.Balance < req.Amount {
-		tx.Rollback()
-		c.JSON(400, gin.H{"error": "Insufficient balance"})
-		return
+	var total model.Money
+	for _, bet := range bets {
+		total += bet.Amount
 	}
+	c.JSON(201, gin.H{"bets": bets, "total": total})
+}
 
-	if err := tx.Model(&user).Update("balance", gorm.Expr("balance - ?", req.Amount)).Error; err != nil {
-		tx.Rollback()
-		c.JSON(500, gin.H{"error": "Failed to deduct balance"})
+// CancelBet withdraws a pending bet before the round's cutoff and refunds it
+func (h *Handler) CancelBet(c *gin.Context) {
+	userID, ok := GetUserIDFromContext(c)
+	if !ok {
+		c.JSON(401, gin.H{"error": "Not authenticated"})
 		return
 	}
 
-	bet := model.PC28Bet{
-		UserID:   userID,
-		RoundID:  req.RoundID,
-		BetType:  model.BetType(req.BetType),
-		BetValue: req.BetValue,
-		Amount:   req.Amount,
-		Odds:     odds,
-		Status:   model.BetStatusPending,
+	id, err := ValidateID(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid bet ID"})
+		return
 	}
 
-	if err := tx.Create(&bet).Error; err != nil {
-		tx.Rollback()
-		c.JSON(500, gin.H{"error": "Failed to create bet"})
+	bet, balance, err := h.betSvc.Cancel(userID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrBetNotFound):
+			c.JSON(404, gin.H{"error": "Bet not found"})
+		case errors.Is(err, service.ErrBetCancelDisabled):
+			c.JSON(403, gin.H{"error": "Bet cancellation is disabled"})
+		case errors.Is(err, service.ErrBetNotCancellable),
+			errors.Is(err, service.ErrCancelLimitReached):
+			c.JSON(400, gin.H{"error": err.Error()})
+		default:
+			respondBetError(c, err, "Failed to cancel bet")
+		}
 		return
 	}
 
-	tx.Commit()
-	c.JSON(201, bet)
+	h.hub.SendBalance(userID, balance, "bet_cancelled")
+	c.JSON(200, gin.H{"bet": bet, "balance": balance})
+}
+
+// respondBetError maps a bet service error to a response
+func respondBetError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrRoundNotFound):
+		c.JSON(404, gin.H{"error": "Round not found"})
+	case errors.Is(err, service.ErrRoomNotFound):
+		c.JSON(404, gin.H{"error": "Room not found"})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(404, gin.H{"error": "User not found"})
+	case errors.Is(err, service.ErrInsufficientBalance):
+		c.JSON(400, gin.H{"error": "Insufficient balance"})
+	case errors.Is(err, service.ErrBettingClosed),
+		errors.Is(err, service.ErrInvalidBet),
+		errors.Is(err, service.ErrInvalidChase):
+		c.JSON(400, gin.H{"error": err.Error()})
+	default:
+		c.JSON(500, gin.H{"error": fallback})
+	}
 }
 
 // GetUserBets returns user's bet history
+// Query: room (optional; all rooms when omitted)
 func (h *Handler) GetUserBets(c *gin.Context) {
 	userID, ok := GetUserIDFromContext(c)
 	if !ok {
@@ -225,9 +501,18 @@ func (h *Handler) GetUserBets(c *gin.Context) {
 		return
 	}
 
+	query := h.db.Where("user_id = ?", userID)
+	if c.Query("room") != "" {
+		room := h.queryRoom(c)
+		if room == nil {
+			return
+		}
+		query = query.Where("round_id IN (?)",
+			h.db.Model(&model.PC28Round{}).Select("id").Where("room_id = ?", room.ID))
+	}
+
 	var bets []model.PC28Bet
-	h.db.Where("user_id = ?", userID).
-		Preload("Round").
+	query.Preload("Round").
 		Order("id desc").
 		Limit(50).
 		Find(&bets)
@@ -235,15 +520,32 @@ func (h *Handler) GetUserBets(c *gin.Context) {
 }
 
 // HandleWebSocket handles WebSocket connections
+// Query: room (optional, comma-separated room codes to subscribe to, defaults to pc28),
+// token (optional player token; enables personal messages such as balance updates)
 func (h *Handler) HandleWebSocket(c *gin.Context) {
+	rooms := c.DefaultQuery("room", model.DefaultRoomCode)
+	var topics []string
+	for _, code := range strings.Split(rooms, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			topics = append(topics, ws.RoomTopic(code))
+		}
+	}
+
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		h.logger.Errorf("WebSocket upgrade failed: %v", err)
 		return
 	}
 
+	// Browsers cannot set headers on a WebSocket handshake, so the token comes
+	// in the query; an invalid token just yields an anonymous connection
 	userID := uint(0)
-	client := ws.NewClient(h.hub, conn, userID)
+	if token := c.Query("token"); token != "" {
+		if claims, err := validateToken(token); err == nil && claims.Type == "player" {
+			userID = claims.ID
+		}
+	}
+	client := ws.NewClient(h.hub, conn, userID, topics...)
 	h.hub.Register(client)
 
 	go client.WritePump()
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+
+	"pcgame/backend/internal/model"
+	"pcgame/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RoomHandler handles game room management
+type RoomHandler struct {
+	db      *gorm.DB
+	roomSvc *service.RoomService
+}
+
+// NewRoomHandler creates a new room handler
+func NewRoomHandler(db *gorm.DB) *RoomHandler {
+	return &RoomHandler{
+		db:      db,
+		roomSvc: service.NewRoomService(db),
+	}
+}
+
+// SetupRoomRoutes sets up game room routes (super_admin only)
+func SetupRoomRoutes(r *gin.RouterGroup, db *gorm.DB) {
+	h := NewRoomHandler(db)
+
+	rooms := r.Group("/rooms")
+	rooms.Use(RequireRole(model.RoleSuperAdmin))
+	{
+		rooms.GET("", h.List)
+		rooms.POST("", h.Create)
+		rooms.PUT("/:id", h.Update)
+	}
+}
+
+// RoomRequest represents a create or update room request
+// Zero cadence and empty odds fall back to the global settings
+type RoomRequest struct {
+	Code          string             `json:"code" binding:"required,max=20"`
+	Name          string             `json:"name" binding:"required,max=100"`
+	GameCode      string             `json:"game_code" binding:"max=20"` // Defaults to pc28; fixed after creation
+	IssuePrefix   string             `json:"issue_prefix" binding:"max=10"`
+	RoundDuration int                `json:"round_duration" binding:"min=0"`
+	BettingWindow int                `json:"betting_window" binding:"min=0"`
+	Odds          map[string]float64 `json:"odds"`
+	NumberOdds    map[int]float64    `json:"number_odds"`
+	Status        string             `json:"status" binding:"omitempty,oneof=active disabled"`
+	SortOrder     int                `json:"sort_order"`
+}
+
+// apply copies the request onto a room
+func (req *RoomRequest) apply(room *model.GameRoom) {
+	room.Name = req.Name
+	room.IssuePrefix = req.IssuePrefix
+	room.RoundDuration = req.RoundDuration
+	room.BettingWindow = req.BettingWindow
+	room.SortOrder = req.SortOrder
+	room.Status = req.Status
+	if room.Status == "" {
+		room.Status = "active"
+	}
+
+	odds, _ := json.Marshal(req.Odds)
+	if req.Odds == nil {
+		odds = []byte("{}")
+	}
+	numberOdds, _ := json.Marshal(req.NumberOdds)
+	if req.NumberOdds == nil {
+		numberOdds = []byte("{}")
+	}
+	room.Odds = string(odds)
+	room.NumberOdds = string(numberOdds)
+}
+
+// List returns all rooms, including disabled ones
+func (h *RoomHandler) List(c *gin.Context) {
+	var rooms []model.GameRoom
+	h.db.Order("sort_order asc, id asc").Find(&rooms)
+	c.JSON(200, rooms)
+}
+
+// Create creates a new room; the scheduler opens its first round on the next tick
+func (h *RoomHandler) Create(c *gin.Context) {
+	var req RoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	var count int64
+	h.db.Model(&model.GameRoom{}).Where("code = ?", req.Code).Count(&count)
+	if count > 0 {
+		c.JSON(400, gin.H{"error": "Room code already exists"})
+		return
+	}
+
+	room := model.GameRoom{Code: req.Code, GameCode: req.GameCode}
+	if room.GameCode == "" {
+		room.GameCode = model.GameCodePC28
+	}
+	req.apply(&room)
+
+	if err := h.roomSvc.Save(&room); err != nil {
+		h.respondSaveError(c, err)
+		return
+	}
+
+	c.JSON(201, room)
+}
+
+// Update updates a room; the code and game cannot be changed and new cadence and odds
+// take effect from the room's next round
+func (h *RoomHandler) Update(c *gin.Context) {
+	id, err := ValidateID(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req RoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	room, err := h.roomSvc.GetByID(id)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "Room not found"})
+		return
+	}
+
+	if req.Code != room.Code {
+		c.JSON(400, gin.H{"error": "Room code cannot be changed"})
+		return
+	}
+	if req.GameCode != "" && req.GameCode != room.GameCode {
+		c.JSON(400, gin.H{"error": "Room game cannot be changed"})
+		return
+	}
+	if room.Code == model.DefaultRoomCode && req.Status == "disabled" {
+		c.JSON(400, gin.H{"error": "Default room cannot be disabled"})
+		return
+	}
+
+	req.apply(room)
+
+	if err := h.roomSvc.Save(room); err != nil {
+		h.respondSaveError(c, err)
+		return
+	}
+
+	c.JSON(200, room)
+}
+
+func (h *RoomHandler) respondSaveError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrInvalidRoom) {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(500, gin.H{"error": "Failed to save room"})
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"errors"
+
+	"pcgame/backend/internal/model"
+	"pcgame/backend/internal/service"
+	ws "pcgame/backend/internal/websocket"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RoundHandler handles admin round management
+type RoundHandler struct {
+	db       *gorm.DB
+	hub      *ws.Hub
+	logger   *zap.SugaredLogger
+	roundSvc *service.RoundService
+	roomSvc  *service.RoomService
+}
+
+// NewRoundHandler creates a new round handler
+func NewRoundHandler(db *gorm.DB, hub *ws.Hub, logger *zap.SugaredLogger) *RoundHandler {
+	return &RoundHandler{
+		db:       db,
+		hub:      hub,
+		logger:   logger,
+		roundSvc: service.NewRoundService(db),
+		roomSvc:  service.NewRoomService(db),
+	}
+}
+
+// SetupRoundRoutes sets up admin round routes
+func SetupRoundRoutes(r *gin.RouterGroup, db *gorm.DB, hub *ws.Hub, logger *zap.SugaredLogger) {
+	h := NewRoundHandler(db, hub, logger)
+
+	rounds := r.Group("/rounds")
+	rounds.Use(RequireRole(model.RoleSuperAdmin, model.RoleAdmin))
+	{
+		rounds.POST("/:id/void", h.Void)
+		rounds.POST("/:id/resettle", RequireRole(model.RoleSuperAdmin), h.Resettle)
+		rounds.GET("/:id/corrections", h.Corrections)
+		rounds.GET("/recovery", h.RecoveryRuns)
+	}
+}
+
+// VoidRoundRequest represents a void round request
+type VoidRoundRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// Void voids a round that has not been settled and refunds all pending bets
+func (h *RoundHandler) Void(c *gin.Context) {
+	id, err := ValidateID(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req VoidRoundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.roundSvc.VoidRound(id, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRoundNotFound):
+			c.JSON(404, gin.H{"error": "Round not found"})
+		case errors.Is(err, service.ErrRoundNotVoidable):
+			c.JSON(400, gin.H{"error": "Round is already settled or void"})
+		default:
+			h.logger.Errorf("Failed to void round %d: %v", id, err)
+			c.JSON(500, gin.H{"error": "Failed to void round"})
+		}
+		return
+	}
+
+	adminID, _ := c.Get("admin_id")
+	h.logger.Infof("Admin %v voided round %s: %s (%d bets, %s refunded)",
+		adminID, res.Round.IssueNumber, req.Reason, res.RefundedBets, res.RefundedAmount)
+
+	if room, err := h.roomSvc.GetByID(res.Round.RoomID); err == nil {
+		h.hub.BroadcastRoundVoid(ws.RoomTopic(room.Code), gin.H{
+			"room":            room.Code,
+			"round_id":        res.Round.ID,
+			"issue_number":    res.Round.IssueNumber,
+			"reason":          req.Reason,
+			"refunded_bets":   res.RefundedBets,
+			"refunded_amount": res.RefundedAmount,
+		})
+	}
+
+	c.JSON(200, res)
+}
+
+// ResettleRoundRequest represents a result correction request
+type ResettleRoundRequest struct {
+	KenoData []int  `json:"keno_data" binding:"required,len=20"`
+	Reason   string `json:"reason" binding:"required,max=255"`
+}
+
+// Resettle corrects the draw of a settled round and re-settles its bets (super_admin only)
+func (h *RoundHandler) Resettle(c *gin.Context) {
+	id, err := ValidateID(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req ResettleRoundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID, _ := c.Get("admin_id")
+
+	res, err := h.roundSvc.ResettleRound(id, req.KenoData, req.Reason, adminID.(uint))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRoundNotFound):
+			c.JSON(404, gin.H{"error": "Round not found"})
+		case errors.Is(err, service.ErrRoundNotSettled):
+			c.JSON(400, gin.H{"error": "Round is not settled"})
+		case errors.Is(err, service.ErrInvalidKenoData):
+			c.JSON(400, gin.H{"error": err.Error()})
+		default:
+			h.logger.Errorf("Failed to re-settle round %d: %v", id, err)
+			c.JSON(500, gin.H{"error": "Failed to re-settle round"})
+		}
+		return
+	}
+
+	h.logger.Infof("Admin %v re-settled round %s: sum %d -> %d (%d bets changed)",
+		adminID, res.Round.IssueNumber, res.Correction.OldSum, res.Correction.NewSum, res.Correction.AffectedBets)
+
+	// Publish the corrected result to the room and the new balance to each affected player
+	if room, game, err := h.roomSvc.GameForRound(&res.Round); err == nil {
+		h.hub.BroadcastResult(ws.RoomTopic(room.Code), gin.H{
+			"room":         room.Code,
+			"game":         game.Code(),
+			"round_id":     res.Round.ID,
+			"issue_number": res.Round.IssueNumber,
+			"keno_data":    req.KenoData,
+			"result_a":     res.Round.ResultA,
+			"result_b":     res.Round.ResultB,
+			"result_c":     res.Round.ResultC,
+			"sum":          res.Round.Sum,
+			"corrected":    true,
+			"reason":       req.Reason,
+		})
+	}
+	for _, userID := range res.UserIDs {
+		h.hub.SendBalance(userID, res.Balances[userID], "correction")
+	}
+
+	c.JSON(200, res)
+}
+
+// Corrections returns the correction audit trail of a round
+func (h *RoundHandler) Corrections(c *gin.Context) {
+	id, err := ValidateID(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var corrections []model.RoundCorrection
+	h.db.Where("round_id = ?", id).Order("id desc").Find(&corrections)
+	c.JSON(200, corrections)
+}
+
+// RecoveryRuns returns the summaries of recent startup recovery passes
+func (h *RoundHandler) RecoveryRuns(c *gin.Context) {
+	var runs []model.RecoveryRun
+	h.db.Order("id desc").Limit(20).Find(&runs)
+	c.JSON(200, runs)
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"errors"
+
+	"pcgame/backend/internal/model"
+	"pcgame/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SettingsHandler handles game settings management
+type SettingsHandler struct {
+	db          *gorm.DB
+	gameSvc     *service.GameService
+	settingsSvc *service.SettingsService
+}
+
+// NewSettingsHandler creates a new settings handler
+func NewSettingsHandler(db *gorm.DB) *SettingsHandler {
+	return &SettingsHandler{
+		db:          db,
+		gameSvc:     service.NewGameService(),
+		settingsSvc: service.NewSettingsService(db),
+	}
+}
+
+// SetupSettingsRoutes sets up game settings routes (super_admin only)
+func SetupSettingsRoutes(r *gin.RouterGroup, db *gorm.DB) {
+	h := NewSettingsHandler(db)
+
+	settings := r.Group("/settings")
+	settings.Use(RequireRole(model.RoleSuperAdmin))
+	{
+		settings.GET("", h.Get)
+		settings.PUT("", h.Update)
+	}
+
+	r.GET("/odds/analysis", RequireRole(model.RoleSuperAdmin, model.RoleAdmin), h.OddsAnalysis)
+}
+
+// Get returns the current game settings
+func (h *SettingsHandler) Get(c *gin.Context) {
+	settings, err := h.settingsSvc.Get()
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load settings"})
+		return
+	}
+	c.JSON(200, settings)
+}
+
+// UpdateSettingsRequest represents an update settings request
+// Odds and NumberOdds are merged into the current tables
+type UpdateSettingsRequest struct {
+	RoundDuration int                `json:"round_duration" binding:"required,gt=0"`
+	BettingWindow int                `json:"betting_window" binding:"required,gt=0"`
+	BetCutoff     *int               `json:"bet_cutoff" binding:"omitempty,min=0"`   // Unchanged when omitted
+	BetCancel     *bool              `json:"bet_cancel"`                             // Unchanged when omitted
+	CancelLimit   *int               `json:"cancel_limit" binding:"omitempty,min=0"` // Unchanged when omitted
+	MinBet        model.Money        `json:"min_bet" binding:"required,gt=0"`
+	MaxBet        model.Money        `json:"max_bet" binding:"required,gt=0"`
+	Odds          map[string]float64 `json:"odds"`
+	NumberOdds    map[int]float64    `json:"number_odds"`
+}
+
+// Update saves game settings; they take effect from the next round
+func (h *SettingsHandler) Update(c *gin.Context) {
+	var req UpdateSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := h.settingsSvc.Get()
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load settings"})
+		return
+	}
+
+	settings.RoundDuration = req.RoundDuration
+	settings.BettingWindow = req.BettingWindow
+	if req.BetCutoff != nil {
+		settings.BetCutoff = *req.BetCutoff
+	}
+	if req.BetCancel != nil {
+		settings.BetCancel = *req.BetCancel
+	}
+	if req.CancelLimit != nil {
+		settings.CancelLimit = *req.CancelLimit
+	}
+	settings.MinBet = req.MinBet
+	settings.MaxBet = req.MaxBet
+
+	for betType, odds := range req.Odds {
+		if _, ok := settings.Odds[betType]; !ok {
+			c.JSON(400, gin.H{"error": "Unknown bet type: " + betType})
+			return
+		}
+		settings.Odds[betType] = odds
+	}
+	for sum, odds := range req.NumberOdds {
+		if _, ok := settings.NumberOdds[sum]; !ok {
+			c.JSON(400, gin.H{"error": "Number odds must be for sums 0-27"})
+			return
+		}
+		settings.NumberOdds[sum] = odds
+	}
+
+	adminID, _ := c.Get("admin_id")
+	if err := h.settingsSvc.Save(settings, adminID.(uint)); err != nil {
+		if errors.Is(err, service.ErrInvalidSettings) {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(500, gin.H{"error": "Failed to save settings"})
+		return
+	}
+
+	c.JSON(200, settings)
+}
+
+// OddsAnalysis returns the exact win probability, RTP and house edge of every
+// bet type under the current odds
+// Query: room (optional; global odds when omitted)
+func (h *SettingsHandler) OddsAnalysis(c *gin.Context) {
+	settings, err := h.settingsSvc.Get()
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load settings"})
+		return
+	}
+
+	if code := c.Query("room"); code != "" {
+		room, err := service.NewRoomService(h.db).GetByCode(code)
+		if err != nil {
+			c.JSON(404, gin.H{"error": "Room not found"})
+			return
+		}
+		if room.GameCode != model.GameCodePC28 {
+			c.JSON(400, gin.H{"error": "Odds analysis is only available for PC28"})
+			return
+		}
+		settings.ApplyRoom(room)
+	}
+	c.JSON(200, h.gameSvc.AnalyzeOdds(settings))
+}
@@ -1,7 +1,12 @@
 package api
 
 import (
+	"errors"
+
+	"pcgame/backend/internal/config"
 	"pcgame/backend/internal/model"
+	"pcgame/backend/internal/service"
+	ws "pcgame/backend/internal/websocket"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
@@ -10,17 +15,23 @@ import (
 
 // UserHandler handles user-related requests
 type UserHandler struct {
-	db *gorm.DB
+	db            *gorm.DB
+	hub           *ws.Hub
+	commissionSvc *service.CommissionService
 }
 
 // NewUserHandler creates a new user handler
-func NewUserHandler(db *gorm.DB) *UserHandler {
-	return &UserHandler{db: db}
+func NewUserHandler(db *gorm.DB, hub *ws.Hub, referral config.ReferralConfig) *UserHandler {
+	return &UserHandler{
+		db:            db,
+		hub:           hub,
+		commissionSvc: service.NewCommissionService(db, model.RateFromFloat(referral.CommissionRate), referral.AutoCredit),
+	}
 }
 
 // SetupUserRoutes sets up user routes
-func SetupUserRoutes(r *gin.RouterGroup, db *gorm.DB) {
-	h := NewUserHandler(db)
+func SetupUserRoutes(r *gin.RouterGroup, db *gorm.DB, hub *ws.Hub, referral config.ReferralConfig) {
+	h := NewUserHandler(db, hub, referral)
 
 	// Admin-only routes
 	users := r.Group("/users")
@@ -38,6 +49,9 @@ func SetupUserRoutes(r *gin.RouterGroup, db *gorm.DB) {
 		player.GET("/referrals", h.GetReferrals)
 		player.GET("/referral-stats", h.GetReferralStats)
 		player.GET("/earnings", h.GetEarnings)
+		player.POST("/commissions/claim", h.ClaimCommission)
+		player.GET("/transactions", NewWalletHandler(db).PlayerTransactions)
+		SetupPlayerFundingRoutes(player, db, hub)
 	}
 }
 
@@ -73,6 +87,26 @@ func (h *UserHandler) List(c *gin.Context) {
 	c.JSON(200, users)
 }
 
+// userScope returns a subquery of the IDs of the users the calling admin may
+// see, with the same rules as List; ok is false for an unknown role
+func userScope(c *gin.Context, db *gorm.DB) (*gorm.DB, bool) {
+	adminRole, _ := c.Get("admin_role")
+	adminID, _ := c.Get("admin_id")
+
+	query := db.Model(&model.User{}).Select("id")
+	switch adminRole {
+	case model.RoleSuperAdmin:
+		return query, true
+	case model.RoleAdmin:
+		return query.Where("operator_id IN (?)",
+			db.Model(&model.Operator{}).Select("id").Where("created_by_id = ?", adminID)), true
+	case model.RoleOperator:
+		return query.Where("1 = 0"), true
+	default:
+		return nil, false
+	}
+}
+
 // GetCurrentPlayer returns the current player info
 func (h *UserHandler) GetCurrentPlayer(c *gin.Context) {
 	user, ok := GetUserFromContext(c)
@@ -114,6 +148,10 @@ func (h *UserHandler) GetInviteCode(c *gin.Context) {
 	})
 }
 
+// settledBetStatuses are the bets that count toward customer loss; pending,
+// refunded and cancelled stakes were never lost
+var settledBetStatuses = []model.BetStatus{model.BetStatusWon, model.BetStatusLost}
+
 // GetReferrals returns users invited by the current user with bet statistics
 func (h *UserHandler) GetReferrals(c *gin.Context) {
 	userID, ok := GetUserIDFromContext(c)
@@ -127,18 +165,18 @@ func (h *UserHandler) GetReferrals(c *gin.Context) {
 
 	// Calculate bet statistics for each referral
 	type ReferralWithStats struct {
-		ID        uint    `json:"id"`
-		Username  string  `json:"username"`
-		TotalBet  float64 `json:"total_bet"`
-		TotalWin  float64 `json:"total_win"`
-		NetLoss   float64 `json:"net_loss"`
-		CreatedAt string  `json:"created_at"`
+		ID        uint        `json:"id"`
+		Username  string      `json:"username"`
+		TotalBet  model.Money `json:"total_bet"`
+		TotalWin  model.Money `json:"total_win"`
+		NetLoss   model.Money `json:"net_loss"`
+		CreatedAt string      `json:"created_at"`
 	}
 
 	result := make([]ReferralWithStats, 0, len(referrals))
 	for _, r := range referrals {
-		var totalBet, totalWin float64
-		h.db.Model(&model.PC28Bet{}).Where("user_id = ?", r.ID).
+		var totalBet, totalWin model.Money
+		h.db.Model(&model.PC28Bet{}).Where("user_id = ? AND status IN ?", r.ID, settledBetStatuses).
 			Select("COALESCE(SUM(amount), 0)").Scan(&totalBet)
 		h.db.Model(&model.PC28Bet{}).Where("user_id = ? AND status = ?", r.ID, "won").
 			Select("COALESCE(SUM(win_amount), 0)").Scan(&totalWin)
@@ -156,7 +194,8 @@ func (h *UserHandler) GetReferrals(c *gin.Context) {
 	c.JSON(200, result)
 }
 
-// GetReferralStats returns summary statistics for the user's referrals
+// GetReferralStats returns summary statistics for the user's referrals;
+// loss and commission come from the settled daily commission records
 func (h *UserHandler) GetReferralStats(c *gin.Context) {
 	userID, ok := GetUserIDFromContext(c)
 	if !ok {
@@ -170,38 +209,39 @@ func (h *UserHandler) GetReferralStats(c *gin.Context) {
 
 	totalReferrals := len(referralIDs)
 	var activeReferrals int64
-	var totalCustomerLoss float64
-	commissionRate := 0.1 // Default 10%, can be configured later
 
 	if totalReferrals > 0 {
 		// Count active referrals (those who have placed bets)
 		h.db.Model(&model.PC28Bet{}).Where("user_id IN ?", referralIDs).
 			Distinct("user_id").Count(&activeReferrals)
-
-		// Calculate total customer loss (total bet - total win)
-		var totalBet, totalWin float64
-		h.db.Model(&model.PC28Bet{}).Where("user_id IN ?", referralIDs).
-			Select("COALESCE(SUM(amount), 0)").Scan(&totalBet)
-		h.db.Model(&model.PC28Bet{}).Where("user_id IN ? AND status = ?", referralIDs, "won").
-			Select("COALESCE(SUM(win_amount), 0)").Scan(&totalWin)
-		totalCustomerLoss = totalBet - totalWin
 	}
 
-	totalCommission := totalCustomerLoss * commissionRate
-	if totalCommission < 0 {
-		totalCommission = 0 // No negative commission
+	var totals struct {
+		CustomerLoss model.Money
+		Commission   model.Money
+		Pending      model.Money
 	}
+	h.db.Model(&model.ReferralCommission{}).
+		Select("COALESCE(SUM(customer_loss), 0)::BIGINT AS customer_loss, "+
+			"COALESCE(SUM(amount), 0)::BIGINT AS commission, "+
+			"COALESCE(SUM(CASE WHEN status = ? THEN amount ELSE 0 END), 0)::BIGINT AS pending",
+			model.CommissionStatusPending).
+		Where("referrer_id = ?", userID).
+		Scan(&totals)
 
 	c.JSON(200, gin.H{
 		"total_referrals":     totalReferrals,
 		"active_referrals":    activeReferrals,
-		"total_customer_loss": totalCustomerLoss,
-		"total_commission":    totalCommission,
-		"commission_rate":     commissionRate,
+		"total_customer_loss": totals.CustomerLoss,
+		"total_commission":    totals.Commission,
+		"pending_commission":  totals.Pending,
+		"commission_rate":     h.commissionSvc.Rate().Float(),
 	})
 }
 
-// GetEarnings returns daily earnings breakdown
+// GetEarnings returns the daily commissions, newest first; revisions of a
+// day are summed into one entry
+// Query: start_date, end_date (YYYY-MM-DD)
 func (h *UserHandler) GetEarnings(c *gin.Context) {
 	userID, ok := GetUserIDFromContext(c)
 	if !ok {
@@ -209,77 +249,77 @@ func (h *UserHandler) GetEarnings(c *gin.Context) {
 		return
 	}
 
-	startDate := c.Query("start_date")
-	endDate := c.Query("end_date")
-
-	// Get referral IDs
-	var referralIDs []uint
-	h.db.Model(&model.User{}).Where("referrer_id = ?", userID).Pluck("id", &referralIDs)
+	query := h.db.Where("referrer_id = ?", userID)
+	if v := c.Query("start_date"); v != "" {
+		query = query.Where("date >= ?", v)
+	}
+	if v := c.Query("end_date"); v != "" {
+		query = query.Where("date <= ?", v)
+	}
 
-	commissionRate := 0.1 // Default 10%
-	var totalEarnings float64
+	var records []model.ReferralCommission
+	query.Order("date desc, revision asc").Find(&records)
 
 	type DailyEarning struct {
-		Date          string  `json:"date"`
-		CustomerLoss  float64 `json:"customer_loss"`
-		Commission    float64 `json:"commission"`
-		ReferralCount int     `json:"referral_count"`
-	}
-
-	dailyEarnings := make([]DailyEarning, 0)
-
-	if len(referralIDs) > 0 {
-		// Get daily breakdown
-		query := h.db.Model(&model.PC28Bet{}).
-			Select("DATE(created_at) as bet_date, SUM(amount) as total_bet, "+
-				"SUM(CASE WHEN status = 'won' THEN win_amount ELSE 0 END) as total_win, "+
-				"COUNT(DISTINCT user_id) as user_count").
-			Where("user_id IN ?", referralIDs).
-			Group("DATE(created_at)").
-			Order("bet_date DESC")
-
-		if startDate != "" {
-			query = query.Where("DATE(created_at) >= ?", startDate)
-		}
-		if endDate != "" {
-			query = query.Where("DATE(created_at) <= ?", endDate)
-		}
+		Date          string                 `json:"date"`
+		CustomerLoss  model.Money            `json:"customer_loss"`
+		Commission    model.Money            `json:"commission"`
+		ReferralCount int                    `json:"referral_count"`
+		Status        model.CommissionStatus `json:"status"`
+	}
 
-		type DailyResult struct {
-			BetDate   string
-			TotalBet  float64
-			TotalWin  float64
-			UserCount int
+	var totalEarnings, pendingEarnings model.Money
+	dailyEarnings := make([]DailyEarning, 0, len(records))
+	for _, r := range records {
+		totalEarnings += r.Amount
+		if r.Status == model.CommissionStatusPending {
+			pendingEarnings += r.Amount
 		}
 
-		var results []DailyResult
-		query.Scan(&results)
-
-		for _, r := range results {
-			loss := r.TotalBet - r.TotalWin
-			commission := loss * commissionRate
-			if commission < 0 {
-				commission = 0
-			}
-			totalEarnings += commission
-
-			dailyEarnings = append(dailyEarnings, DailyEarning{
-				Date:          r.BetDate,
-				CustomerLoss:  loss,
-				Commission:    commission,
-				ReferralCount: r.UserCount,
-			})
+		n := len(dailyEarnings)
+		if n == 0 || dailyEarnings[n-1].Date != r.Date {
+			dailyEarnings = append(dailyEarnings, DailyEarning{Date: r.Date, Status: model.CommissionStatusPaid})
+			n++
+		}
+		day := &dailyEarnings[n-1]
+		day.CustomerLoss += r.CustomerLoss
+		day.Commission += r.Amount
+		day.ReferralCount = r.ReferralCount // From the latest revision
+		if r.Status == model.CommissionStatusPending {
+			day.Status = model.CommissionStatusPending
 		}
 	}
 
 	c.JSON(200, gin.H{
 		"total_earnings":   totalEarnings,
-		"pending_earnings": 0, // Placeholder for future implementation
-		"settled_earnings": totalEarnings,
+		"pending_earnings": pendingEarnings,
+		"settled_earnings": totalEarnings - pendingEarnings,
 		"daily_earnings":   dailyEarnings,
 	})
 }
 
+// ClaimCommission pays the user's pending commissions to their balance
+func (h *UserHandler) ClaimCommission(c *gin.Context) {
+	userID, ok := GetUserIDFromContext(c)
+	if !ok {
+		c.JSON(401, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	res, err := h.commissionSvc.Claim(userID)
+	if err != nil {
+		if errors.Is(err, service.ErrNoPendingCommission) {
+			c.JSON(400, gin.H{"error": "No pending commission"})
+			return
+		}
+		c.JSON(500, gin.H{"error": "Failed to claim commission"})
+		return
+	}
+
+	h.hub.SendBalance(userID, res.Balance, "commission")
+	c.JSON(200, gin.H{"claimed": res.Claimed, "count": res.Count, "balance": res.Balance})
+}
+
 // ==========================================
 // Player Auth Handlers
 // ==========================================
@@ -330,6 +370,9 @@ type RegisterRequest struct {
 	ReferrerCode string `json:"referrer_code" binding:"omitempty,max=20"`
 }
 
+// signupBonus is credited to every new player
+const signupBonus = 1000 * model.MoneyScale
+
 // Register handles user registration
 func (h *UserHandler) Register(c *gin.Context) {
 	var req RegisterRequest
@@ -354,7 +397,6 @@ func (h *UserHandler) Register(c *gin.Context) {
 	user := model.User{
 		Username: req.Username,
 		Password: string(hashedPassword),
-		Balance:  1000,
 	}
 
 	if req.ReferrerCode != "" {
@@ -374,7 +416,17 @@ func (h *UserHandler) Register(c *gin.Context) {
 		}
 	}
 
-	if err := h.db.Create(&user).Error; err != nil {
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		if err := service.Credit(tx, user.ID, signupBonus, service.LedgerEntry{Type: model.WalletTxSignupBonus}); err != nil {
+			return err
+		}
+		user.Balance = signupBonus
+		return nil
+	})
+	if err != nil {
 		c.JSON(500, gin.H{"error": "Failed to create user"})
 		return
 	}
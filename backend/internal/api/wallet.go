@@ -0,0 +1,124 @@
+package api
+
+import (
+	"strconv"
+
+	"pcgame/backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// WalletHandler serves the wallet ledger
+type WalletHandler struct {
+	db *gorm.DB
+}
+
+// NewWalletHandler creates a new wallet handler
+func NewWalletHandler(db *gorm.DB) *WalletHandler {
+	return &WalletHandler{db: db}
+}
+
+// SetupWalletRoutes sets up the admin ledger search
+func SetupWalletRoutes(r *gin.RouterGroup, db *gorm.DB) {
+	h := NewWalletHandler(db)
+
+	r.GET("/transactions", RequireRole(model.RoleSuperAdmin, model.RoleAdmin), h.List)
+}
+
+// walletTxRow is a ledger entry with its player's username
+type walletTxRow struct {
+	model.WalletTransaction
+	Username string `json:"username"`
+}
+
+// pageParams reads page (1-based) and page_size (default 20, at most 100)
+func pageParams(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	size, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if size < 1 || size > 100 {
+		size = 20
+	}
+	return page, size
+}
+
+// filterTransactions applies the filters shared by players and admins
+// Query: type, start_date, end_date (YYYY-MM-DD, inclusive)
+func filterTransactions(c *gin.Context, query *gorm.DB) *gorm.DB {
+	if t := c.Query("type"); t != "" {
+		query = query.Where("wallet_transactions.type = ?", t)
+	}
+	if d := c.Query("start_date"); d != "" {
+		query = query.Where("DATE(wallet_transactions.created_at) >= ?", d)
+	}
+	if d := c.Query("end_date"); d != "" {
+		query = query.Where("DATE(wallet_transactions.created_at) <= ?", d)
+	}
+	return query
+}
+
+// PlayerTransactions returns the current player's ledger, newest first
+// Query: type, start_date, end_date, page, page_size
+func (h *WalletHandler) PlayerTransactions(c *gin.Context) {
+	userID, ok := GetUserIDFromContext(c)
+	if !ok {
+		c.JSON(401, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	page, size := pageParams(c)
+	query := filterTransactions(c, h.db.Model(&model.WalletTransaction{}).Where("user_id = ?", userID))
+
+	var total int64
+	query.Count(&total)
+
+	txs := make([]model.WalletTransaction, 0)
+	query.Order("id desc").Offset((page - 1) * size).Limit(size).Find(&txs)
+
+	c.JSON(200, gin.H{"data": txs, "total": total, "page": page, "page_size": size})
+}
+
+// List searches the ledger of the players the admin may see, newest first
+// Query: user_id, username, type, bet_id, round_id, start_date, end_date, page, page_size
+func (h *WalletHandler) List(c *gin.Context) {
+	users, ok := userScope(c, h.db)
+	if !ok {
+		c.JSON(403, gin.H{"error": "Access denied"})
+		return
+	}
+
+	query := h.db.Model(&model.WalletTransaction{}).
+		Joins("JOIN users ON users.id = wallet_transactions.user_id").
+		Where("wallet_transactions.user_id IN (?)", users)
+
+	if v := c.Query("user_id"); v != "" {
+		query = query.Where("wallet_transactions.user_id = ?", v)
+	}
+	if v := c.Query("username"); v != "" {
+		query = query.Where("users.username = ?", v)
+	}
+	if v := c.Query("bet_id"); v != "" {
+		query = query.Where("wallet_transactions.bet_id = ?", v)
+	}
+	if v := c.Query("round_id"); v != "" {
+		query = query.Where("wallet_transactions.round_id = ?", v)
+	}
+	query = filterTransactions(c, query)
+
+	page, size := pageParams(c)
+
+	var total int64
+	query.Count(&total)
+
+	rows := make([]walletTxRow, 0)
+	query.Select("wallet_transactions.*, users.username").
+		Order("wallet_transactions.id desc").
+		Offset((page - 1) * size).
+		Limit(size).
+		Scan(&rows)
+
+	c.JSON(200, gin.H{"data": rows, "total": total, "page": page, "page_size": size})
+}
@@ -1,13 +1,21 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	Game      GameConfig
+	Keno      KenoConfig
+	Scheduler SchedulerConfig
+	Wallet    WalletConfig
+	Referral  ReferralConfig
 }
 
 type ServerConfig struct {
@@ -29,6 +37,41 @@ type JWTConfig struct {
 	ExpireHour int
 }
 
+type GameConfig struct {
+	UseMockData    bool   // 使用 Mock 数据
+	RecoveryPolicy string // 启动时处理过期轮次: settle (开奖并结算) 或 void (作废并退款)
+	RecoveryGrace  int    // 截止超过此秒数的轮次才算过期, 更近的交给正常开奖流程
+	ScheduleAhead  int    // 每个房间提前创建的待开放轮次数量
+}
+
+type KenoConfig struct {
+	APIURL        string
+	APIKey        string
+	Timeout       int // 单次请求超时 (秒)
+	MaxRetries    int // 开奖数据未就绪时的最大重试次数
+	RetryInterval int // 重试间隔 (秒)
+
+	// 数据源期号按固定间隔递增: AnchorIssue 在 AnchorTime 开奖, 之后每 DrawInterval 秒一期
+	AnchorIssue  int64
+	AnchorTime   time.Time
+	DrawInterval int
+}
+
+type SchedulerConfig struct {
+	LeaderElection bool  // 多实例部署时只有持有锁的实例运行调度器
+	LeaderLockKey  int64 // Postgres advisory lock key
+	LeaseInterval  int   // 续约/抢锁间隔 (秒)
+}
+
+type WalletConfig struct {
+	AdjustmentApprovalThreshold float64 // 人工调账金额 (绝对值) 超过此值须另一名管理员批准, 0 表示全部须批准
+}
+
+type ReferralConfig struct {
+	CommissionRate float64 // 推荐佣金比例 (下线每日净亏损 × 比例)
+	AutoCredit     bool    // true: 结算时直接入账; false: 待领取, 由推荐人领取
+}
+
 func Load() (*Config, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
@@ -46,6 +89,20 @@ func Load() (*Config, error) {
 	viper.SetDefault("database.sslmode", "disable")
 	viper.SetDefault("jwt.secret", "your-secret-key")
 	viper.SetDefault("jwt.expireHour", 24)
+	viper.SetDefault("game.use_mock_data", true)
+	viper.SetDefault("game.recovery_policy", "settle")
+	viper.SetDefault("game.recovery_grace", 300)
+	viper.SetDefault("game.schedule_ahead", 10)
+	viper.SetDefault("keno.timeout", 3)
+	viper.SetDefault("keno.max_retries", 3)
+	viper.SetDefault("keno.retry_interval", 5)
+	viper.SetDefault("keno.draw_interval", 300)
+	viper.SetDefault("scheduler.leader_election", true)
+	viper.SetDefault("scheduler.leader_lock_key", 280028)
+	viper.SetDefault("scheduler.lease_interval", 5)
+	viper.SetDefault("wallet.adjustment_approval_threshold", 1000)
+	viper.SetDefault("referral.commission_rate", 0.1)
+	viper.SetDefault("referral.auto_credit", true)
 
 	// Auto-bind environment variables
 	viper.AutomaticEnv()
@@ -68,6 +125,39 @@ func Load() (*Config, error) {
 	cfg.Database.SSLMode = viper.GetString("database.sslmode")
 	cfg.JWT.Secret = viper.GetString("jwt.secret")
 	cfg.JWT.ExpireHour = viper.GetInt("jwt.expireHour")
+	cfg.Game.UseMockData = viper.GetBool("game.use_mock_data")
+	cfg.Game.RecoveryPolicy = viper.GetString("game.recovery_policy")
+	cfg.Game.RecoveryGrace = viper.GetInt("game.recovery_grace")
+	cfg.Game.ScheduleAhead = viper.GetInt("game.schedule_ahead")
+	cfg.Keno.APIURL = viper.GetString("keno.api_url")
+	cfg.Keno.APIKey = viper.GetString("keno.api_key")
+	cfg.Keno.Timeout = viper.GetInt("keno.timeout")
+	cfg.Keno.MaxRetries = viper.GetInt("keno.max_retries")
+	cfg.Keno.RetryInterval = viper.GetInt("keno.retry_interval")
+	cfg.Keno.AnchorIssue = viper.GetInt64("keno.anchor_issue")
+	cfg.Keno.DrawInterval = viper.GetInt("keno.draw_interval")
+	cfg.Scheduler.LeaderElection = viper.GetBool("scheduler.leader_election")
+	cfg.Scheduler.LeaderLockKey = viper.GetInt64("scheduler.leader_lock_key")
+	cfg.Scheduler.LeaseInterval = viper.GetInt("scheduler.lease_interval")
+	cfg.Wallet.AdjustmentApprovalThreshold = viper.GetFloat64("wallet.adjustment_approval_threshold")
+	cfg.Referral.CommissionRate = viper.GetFloat64("referral.commission_rate")
+	cfg.Referral.AutoCredit = viper.GetBool("referral.auto_credit")
+
+	// The HTTP source can only name the provider's draw for a round with a schedule
+	if !cfg.Game.UseMockData && cfg.Keno.APIURL != "" {
+		anchor, err := time.Parse(time.RFC3339, viper.GetString("keno.anchor_time"))
+		if err != nil {
+			return nil, fmt.Errorf("keno.anchor_time must be an RFC 3339 time: %w", err)
+		}
+		if cfg.Keno.AnchorIssue <= 0 || cfg.Keno.DrawInterval <= 0 {
+			return nil, fmt.Errorf("keno.anchor_issue and keno.draw_interval must be positive")
+		}
+		// Round durations are multiples of it and must still divide a day
+		if 86400%cfg.Keno.DrawInterval != 0 {
+			return nil, fmt.Errorf("keno.draw_interval must divide a day (86400 seconds) evenly")
+		}
+		cfg.Keno.AnchorTime = anchor
+	}
 
 	return &cfg, nil
 }
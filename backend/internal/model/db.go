@@ -28,11 +28,75 @@ func InitDB(cfg *config.Config) (*gorm.DB, error) {
 }
 
 func AutoMigrate(db *gorm.DB) error {
+	if err := migrateMoney(db); err != nil {
+		return fmt.Errorf("convert money columns: %w", err)
+	}
 	return db.AutoMigrate(
 		&AdminUser{},
+		&GameRoom{},
 		&Operator{},
 		&User{},
 		&PC28Round{},
 		&PC28Bet{},
+		&ChasePlan{},
+		&WalletTransaction{},
+		&FundingRequest{},
+		&BalanceAdjustment{},
+		&ReferralCommission{},
+		&RoundCorrection{},
+		&GameSetting{},
+		&RecoveryRun{},
 	)
 }
+
+// moneyColumns are stored as Money (cents) or Rate (ten-thousandths); earlier
+// versions kept them as decimals
+var moneyColumns = []struct {
+	table, column string
+	scale         int
+}{
+	{"users", "balance", MoneyScale},
+	{"game_settings", "min_bet", MoneyScale},
+	{"game_settings", "max_bet", MoneyScale},
+	{"round_corrections", "old_payout", MoneyScale},
+	{"round_corrections", "new_payout", MoneyScale},
+	{"pc28_bets", "amount", MoneyScale},
+	{"pc28_bets", "odds", RateScale},
+	{"pc28_bets", "win_amount", MoneyScale},
+	{"chase_plans", "base_amount", MoneyScale},
+	{"chase_plans", "reserved", MoneyScale},
+	{"chase_plans", "spent", MoneyScale},
+	{"chase_plans", "refunded", MoneyScale},
+	{"wallet_transactions", "amount", MoneyScale},
+	{"wallet_transactions", "balance_after", MoneyScale},
+}
+
+// migrateMoney converts decimal money columns of an existing database to
+// integers, rounding half away from zero. It must run before AutoMigrate,
+// whose own type change would drop the fraction. Columns that are already
+// BIGINT are skipped, so it is safe on every start; the dropped defaults are
+// restored by AutoMigrate.
+func migrateMoney(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('money_columns'))").Error; err != nil {
+			return err
+		}
+		for _, c := range moneyColumns {
+			var dataType string
+			if err := tx.Raw(`SELECT data_type FROM information_schema.columns
+				WHERE table_schema = CURRENT_SCHEMA() AND table_name = ? AND column_name = ?`,
+				c.table, c.column).Scan(&dataType).Error; err != nil {
+				return err
+			}
+			if dataType == "" || dataType == "bigint" {
+				continue
+			}
+			if err := tx.Exec(fmt.Sprintf(
+				"ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT, ALTER COLUMN %[2]s TYPE BIGINT USING ROUND(%[2]s::NUMERIC * %[3]d)::BIGINT",
+				c.table, c.column, c.scale)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"time"
 
 	"gorm.io/gorm"
@@ -42,7 +43,7 @@ type User struct {
 	gorm.Model
 	Username    string    `gorm:"uniqueIndex;size:50;not null" json:"username"`
 	Password    string    `gorm:"size:255;not null" json:"-"`
-	Balance     float64   `gorm:"default:0" json:"balance"`
+	Balance     Money     `gorm:"default:0" json:"balance"`
 	Role        string    `gorm:"size:20;default:'user'" json:"role"` // user
 	OperatorID  *uint     `gorm:"index" json:"operator_id"`           // 归属运营者
 	Operator    *Operator `gorm:"foreignKey:OperatorID" json:"operator,omitempty"`
@@ -67,6 +68,43 @@ func generateInviteCode() string {
 	return hex.EncodeToString(bytes)
 }
 
+// GameSetting holds the admin-editable game parameters (single row, ID 1)
+type GameSetting struct {
+	gorm.Model
+	RoundDuration int    `gorm:"not null" json:"round_duration"`       // 轮次时长 (秒)
+	BettingWindow int    `gorm:"not null" json:"betting_window"`       // 投注窗口 (秒)
+	BetCutoff     int    `gorm:"not null;default:2" json:"bet_cutoff"` // 截止前停止投注的安全余量 (秒)
+	BetCancel     *bool  `gorm:"default:true" json:"bet_cancel"`       // 是否允许玩家撤单
+	CancelLimit   int    `gorm:"default:0" json:"cancel_limit"`        // 每位玩家每日撤单次数上限, 0 不限
+	MinBet        Money  `gorm:"not null" json:"min_bet"`              // 最小投注额
+	MaxBet        Money  `gorm:"not null" json:"max_bet"`              // 最大投注额
+	Odds          string `gorm:"type:jsonb" json:"odds"`               // JSON map of bet type -> odds
+	NumberOdds    string `gorm:"type:jsonb" json:"number_odds"`        // JSON map of sum -> odds
+	UpdatedByID   *uint  `json:"updated_by_id"`
+}
+
+const (
+	// DefaultRoomCode is the room used when a request does not name one
+	DefaultRoomCode = "pc28"
+	// GameCodePC28 is the code of the built-in PC28 game
+	GameCodePC28 = "pc28"
+)
+
+// GameRoom is an independent PC28 stream with its own cadence, issue prefix and odds
+type GameRoom struct {
+	gorm.Model
+	Code          string `gorm:"uniqueIndex;size:20;not null" json:"code"` // 房间标识, e.g. pc28_3m
+	Name          string `gorm:"size:100;not null" json:"name"`
+	GameCode      string `gorm:"size:20;default:'pc28'" json:"game_code"`    // 玩法, 见 service.RegisterGame
+	IssuePrefix   string `gorm:"size:10" json:"issue_prefix"`                // 期号前缀
+	RoundDuration int    `gorm:"default:0" json:"round_duration"`            // 轮次时长 (秒), 0 = 全局设置
+	BettingWindow int    `gorm:"default:0" json:"betting_window"`            // 投注窗口 (秒), 0 = 全局设置
+	Odds          string `gorm:"type:jsonb;default:'{}'" json:"odds"`        // 覆盖全局赔率 (bet type -> odds)
+	NumberOdds    string `gorm:"type:jsonb;default:'{}'" json:"number_odds"` // 覆盖数字赔率 (sum -> odds)
+	Status        string `gorm:"size:20;default:'active'" json:"status"`     // active, disabled
+	SortOrder     int    `gorm:"default:0" json:"sort_order"`
+}
+
 // RoundStatus represents the status of a game round
 type RoundStatus string
 
@@ -78,18 +116,70 @@ const (
 	RoundStatusVoid    RoundStatus = "void"    // Voided round
 )
 
-// PC28Round represents a single game round
+// PC28Round represents a single game round of any game; the room decides the game
 type PC28Round struct {
 	gorm.Model
+	RoomID      uint        `gorm:"index;not null;default:0" json:"room_id"`          // 所属房间
 	IssueNumber string      `gorm:"uniqueIndex;size:50;not null" json:"issue_number"` // 期号
-	KenoData    string      `gorm:"type:jsonb" json:"keno_data"`                      // JSON array of 20 numbers
+	DrawIssue   string      `gorm:"size:50" json:"draw_issue,omitempty"`              // 数据源期号, 按截止时间对应
+	KenoData    string      `gorm:"type:jsonb;default:'[]'" json:"keno_data"`         // JSON array of 20 numbers
 	ResultA     int         `gorm:"default:0" json:"result_a"`                        // First digit (0-9)
 	ResultB     int         `gorm:"default:0" json:"result_b"`                        // Second digit (0-9)
 	ResultC     int         `gorm:"default:0" json:"result_c"`                        // Third digit (0-9)
 	Sum         int         `gorm:"default:0" json:"sum"`                             // A + B + C (0-27)
 	OpenTime    time.Time   `gorm:"not null" json:"open_time"`                        // When betting opens
 	CloseTime   time.Time   `gorm:"not null" json:"close_time"`                       // When betting closes
+	DrawnAt     *time.Time  `json:"drawn_at"`                                         // When the Keno draw was fetched
 	Status      RoundStatus `gorm:"size:20;default:'pending'" json:"status"`
+	Version     int         `gorm:"not null;default:0" json:"version"`     // Optimistic lock, bumped on every transition
+	VoidReason  string      `gorm:"size:255" json:"void_reason,omitempty"` // 作废原因
+
+	// Provably fair commitment: hash is public from open, seed only after the draw
+	ServerSeedHash string `gorm:"size:64" json:"server_seed_hash,omitempty"`
+	ServerSeed     string `gorm:"size:64" json:"server_seed,omitempty"`
+}
+
+// MarshalJSON hides the server seed until the round has been drawn
+func (r PC28Round) MarshalJSON() ([]byte, error) {
+	type roundJSON PC28Round
+	out := roundJSON(r)
+	if out.DrawnAt == nil {
+		out.ServerSeed = ""
+	}
+	return json.Marshal(out)
+}
+
+// RoundCorrection is the audit record of a re-settled round, keeping both results
+type RoundCorrection struct {
+	gorm.Model
+	RoundID      uint   `gorm:"index;not null" json:"round_id"`
+	AdminID      uint   `gorm:"index" json:"admin_id"`
+	Reason       string `gorm:"size:255" json:"reason"`
+	OldKenoData  string `gorm:"type:jsonb" json:"old_keno_data"`
+	OldResultA   int    `json:"old_result_a"`
+	OldResultB   int    `json:"old_result_b"`
+	OldResultC   int    `json:"old_result_c"`
+	OldSum       int    `json:"old_sum"`
+	NewKenoData  string `gorm:"type:jsonb" json:"new_keno_data"`
+	NewResultA   int    `json:"new_result_a"`
+	NewResultB   int    `json:"new_result_b"`
+	NewResultC   int    `json:"new_result_c"`
+	NewSum       int    `json:"new_sum"`
+	AffectedBets int    `json:"affected_bets"` // Bets whose outcome changed
+	OldPayout    Money  `json:"old_payout"`    // Total winnings before correction
+	NewPayout    Money  `json:"new_payout"`    // Total winnings after correction
+}
+
+// RecoveryRun is the summary of a startup pass over rounds left stale by downtime
+type RecoveryRun struct {
+	gorm.Model
+	Policy     string    `gorm:"size:20" json:"policy"` // settle, void
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Settled    int       `json:"settled"`
+	Voided     int       `json:"voided"`
+	Failed     int       `json:"failed"`
+	Details    string    `gorm:"type:jsonb;default:'[]'" json:"details"` // Per-round actions
 }
 
 // BetType represents the type of bet placed
@@ -105,16 +195,26 @@ const (
 	BetTypeBigEven   BetType = "big_even"   // Big + Even
 	BetTypeSmallOdd  BetType = "small_odd"  // Small + Odd
 	BetTypeSmallEven BetType = "small_even" // Small + Even
+
+	BetTypeExtremeBig   BetType = "extreme_big"   // 极大: 22-27
+	BetTypeExtremeSmall BetType = "extreme_small" // 极小: 0-5
+	BetTypeLeopard      BetType = "leopard"       // 豹子: A = B = C
+	BetTypePair         BetType = "pair"          // 对子: exactly two digits equal
+	BetTypeStraight     BetType = "straight"      // 顺子: three consecutive digits, any order
+	BetTypeDigitA       BetType = "digit_a"       // A equals bet value (0-9)
+	BetTypeDigitB       BetType = "digit_b"       // B equals bet value (0-9)
+	BetTypeDigitC       BetType = "digit_c"       // C equals bet value (0-9)
 )
 
 // BetStatus represents the status of a bet
 type BetStatus string
 
 const (
-	BetStatusPending  BetStatus = "pending"  // Waiting for result
-	BetStatusWon      BetStatus = "won"      // User won
-	BetStatusLost     BetStatus = "lost"     // User lost
-	BetStatusRefunded BetStatus = "refunded" // Bet refunded (void round)
+	BetStatusPending   BetStatus = "pending"   // Waiting for result
+	BetStatusWon       BetStatus = "won"       // User won
+	BetStatusLost      BetStatus = "lost"      // User lost
+	BetStatusRefunded  BetStatus = "refunded"  // Bet refunded (void round)
+	BetStatusCancelled BetStatus = "cancelled" // Withdrawn by the player before the cutoff
 )
 
 // PC28Bet represents a bet placed by a user
@@ -126,8 +226,184 @@ type PC28Bet struct {
 	Round     PC28Round `gorm:"foreignKey:RoundID" json:"-"`
 	BetType   BetType   `gorm:"size:20;not null" json:"bet_type"`
 	BetValue  int       `gorm:"default:0" json:"bet_value"` // For number bets, the specific number
-	Amount    float64   `gorm:"not null" json:"amount"`
-	Odds      float64   `gorm:"not null" json:"odds"`
+	Amount    Money     `gorm:"not null" json:"amount"`
+	Odds      Rate      `gorm:"not null" json:"odds"`
 	Status    BetStatus `gorm:"size:20;default:'pending'" json:"status"`
-	WinAmount float64   `gorm:"default:0" json:"win_amount"`
+	WinAmount Money     `gorm:"default:0" json:"win_amount"`
+
+	ChasePlanID *uint      `gorm:"index" json:"chase_plan_id,omitempty"` // Set when placed by a chase plan
+	CancelledAt *time.Time `gorm:"index" json:"cancelled_at,omitempty"`
+}
+
+// ChaseStatus represents the status of a chase plan
+type ChaseStatus string
+
+const (
+	ChaseStatusActive    ChaseStatus = "active"    // Still placing bets
+	ChaseStatusCompleted ChaseStatus = "completed" // Every round was placed
+	ChaseStatusStopped   ChaseStatus = "stopped"   // Stopped on a win or a rejected bet
+	ChaseStatusCancelled ChaseStatus = "cancelled" // Cancelled by the player
+)
+
+// ChasePlan places the same bet on the next rounds of a room (追号).
+// The stake of every planned round is reserved from the balance when the
+// plan is created; stakes never placed are refunded when it ends early.
+type ChasePlan struct {
+	gorm.Model
+	UserID       uint        `gorm:"index;not null" json:"user_id"`
+	User         User        `gorm:"foreignKey:UserID" json:"-"`
+	RoomID       uint        `gorm:"index;not null" json:"room_id"`
+	BetType      BetType     `gorm:"size:20;not null" json:"bet_type"`
+	BetValue     int         `gorm:"default:0" json:"bet_value"`
+	BaseAmount   Money       `gorm:"not null" json:"base_amount"`          // Stake of the first round
+	Multiplier   float64     `gorm:"not null;default:1" json:"multiplier"` // Stake factor applied each round
+	TotalRounds  int         `gorm:"not null" json:"total_rounds"`
+	PlacedRounds int         `gorm:"default:0" json:"placed_rounds"`
+	StopOnWin    bool        `gorm:"default:false" json:"stop_on_win"`
+	Reserved     Money       `gorm:"not null" json:"reserved"`  // Sum of all planned stakes
+	Spent        Money       `gorm:"default:0" json:"spent"`    // Stakes placed so far
+	Refunded     Money       `gorm:"default:0" json:"refunded"` // Unplaced stakes returned
+	Status       ChaseStatus `gorm:"size:20;index;default:'active'" json:"status"`
+	StopReason   string      `gorm:"size:255" json:"stop_reason,omitempty"`
+	LastRoundID  uint        `gorm:"default:0" json:"last_round_id"` // Round of the latest placed bet
+}
+
+// WalletTxType is the kind of a wallet transaction
+type WalletTxType string
+
+const (
+	WalletTxOpening          WalletTxType = "opening"           // Balance held before the ledger existed
+	WalletTxSignupBonus      WalletTxType = "signup_bonus"      // Registration credit
+	WalletTxBet              WalletTxType = "bet"               // Stake of a bet
+	WalletTxBetCancel        WalletTxType = "bet_cancel"        // Stake returned by a cancelled bet
+	WalletTxPayout           WalletTxType = "payout"            // Winnings of a settled round
+	WalletTxRefund           WalletTxType = "refund"            // Stakes returned by a voided round
+	WalletTxCorrection       WalletTxType = "correction"        // Payout difference after a re-settlement
+	WalletTxChaseReserve     WalletTxType = "chase_reserve"     // Stakes reserved by a chase plan
+	WalletTxChaseRefund      WalletTxType = "chase_refund"      // Unplaced chase stakes returned
+	WalletTxDepositRequest   WalletTxType = "deposit_request"   // Deposit submitted, nothing credited yet (zero amount)
+	WalletTxDeposit          WalletTxType = "deposit"           // Approved deposit
+	WalletTxDepositRejected  WalletTxType = "deposit_rejected"  // Deposit declined, nothing credited (zero amount)
+	WalletTxWithdrawal       WalletTxType = "withdrawal"        // Amount held by a withdrawal request
+	WalletTxWithdrawalPaid   WalletTxType = "withdrawal_paid"   // Held amount paid out on approval (zero amount)
+	WalletTxWithdrawalRefund WalletTxType = "withdrawal_refund" // Hold released by a rejected withdrawal
+	WalletTxAdjustment       WalletTxType = "adjustment"        // Manual change by an admin
+	WalletTxCommission       WalletTxType = "commission"        // Referral commission paid to the referrer
+)
+
+// WalletTransaction is one entry of a player's wallet ledger (账变记录).
+// Every change to User.Balance writes one in the same transaction, so the
+// entries of a player always add up to the balance.
+type WalletTransaction struct {
+	gorm.Model
+	UserID       uint         `gorm:"index;not null" json:"user_id"`
+	User         User         `gorm:"foreignKey:UserID" json:"-"`
+	Type         WalletTxType `gorm:"size:20;index;not null" json:"type"`
+	Amount       Money        `gorm:"not null" json:"amount"`        // Signed: positive credits, negative debits
+	BalanceAfter Money        `gorm:"not null" json:"balance_after"` // Balance once this entry is applied
+	BetID        *uint        `gorm:"index" json:"bet_id,omitempty"`
+	RoundID      *uint        `gorm:"index" json:"round_id,omitempty"`
+	RefType      string       `gorm:"size:20" json:"ref_type,omitempty"` // Other source, e.g. chase_plan
+	RefID        *uint        `json:"ref_id,omitempty"`
+	Note         string       `gorm:"size:255" json:"note,omitempty"`
+}
+
+// FundingType is the direction of a funding request
+type FundingType string
+
+const (
+	FundingDeposit    FundingType = "deposit"    // 充值
+	FundingWithdrawal FundingType = "withdrawal" // 提现
+)
+
+// FundingStatus represents the review state of a funding request
+type FundingStatus string
+
+const (
+	FundingStatusPending  FundingStatus = "pending"  // Awaiting admin review
+	FundingStatusApproved FundingStatus = "approved" // Deposit credited or withdrawal paid out
+	FundingStatusRejected FundingStatus = "rejected" // Nothing credited; a withdrawal's hold is released
+)
+
+// FundingRequest is a player's deposit or withdrawal awaiting admin review
+// (充值/提现申请). A deposit credits nothing until approved; a withdrawal
+// takes its amount from the balance when submitted and holds it until
+// reviewed, so the same funds cannot be bet or withdrawn twice.
+type FundingRequest struct {
+	gorm.Model
+	UserID       uint          `gorm:"index;not null" json:"user_id"`
+	User         User          `gorm:"foreignKey:UserID" json:"-"`
+	Type         FundingType   `gorm:"size:20;index;not null" json:"type"`
+	Amount       Money         `gorm:"not null" json:"amount"`
+	Status       FundingStatus `gorm:"size:20;index;default:'pending'" json:"status"`
+	Method       string        `gorm:"size:20" json:"method"`                 // e.g. bank, alipay
+	Account      string        `gorm:"size:100" json:"account"`               // Payer reference or payee account
+	Note         string        `gorm:"size:255" json:"note,omitempty"`        // From the player
+	ReviewedByID *uint         `gorm:"index" json:"reviewed_by_id,omitempty"` // Admin who approved or rejected it
+	ReviewedAt   *time.Time    `json:"reviewed_at,omitempty"`
+	ReviewNote   string        `gorm:"size:255" json:"review_note,omitempty"`
+}
+
+// AdjustmentReason is the reason code of a manual balance adjustment
+type AdjustmentReason string
+
+const (
+	AdjustmentCompensation AdjustmentReason = "compensation" // 补偿
+	AdjustmentCorrection   AdjustmentReason = "correction"   // 纠正错误入账
+	AdjustmentPromotion    AdjustmentReason = "promotion"    // 活动奖励
+	AdjustmentRecovery     AdjustmentReason = "recovery"     // 追回 (如重复入账)
+	AdjustmentOther        AdjustmentReason = "other"        // 其他, 须在备注中说明
+)
+
+// AdjustmentStatus represents the state of a manual balance adjustment
+type AdjustmentStatus string
+
+const (
+	AdjustmentStatusPending  AdjustmentStatus = "pending"  // Awaiting a second admin
+	AdjustmentStatusApplied  AdjustmentStatus = "applied"  // Balance changed
+	AdjustmentStatusRejected AdjustmentStatus = "rejected" // Declined; balance untouched
+)
+
+// BalanceAdjustment is a manual change to a player's balance by an admin
+// (人工调账). Amounts above the configured threshold wait for a second
+// admin's approval before the balance changes.
+type BalanceAdjustment struct {
+	gorm.Model
+	UserID        uint             `gorm:"index;not null" json:"user_id"`
+	User          User             `gorm:"foreignKey:UserID" json:"-"`
+	Amount        Money            `gorm:"not null" json:"amount"` // Signed: positive credits, negative debits
+	Reason        AdjustmentReason `gorm:"size:20;index;not null" json:"reason"`
+	Note          string           `gorm:"size:255;not null" json:"note"`
+	Status        AdjustmentStatus `gorm:"size:20;index;default:'pending'" json:"status"`
+	RequestedByID uint             `gorm:"index;not null" json:"requested_by_id"`
+	ReviewedByID  *uint            `gorm:"index" json:"reviewed_by_id,omitempty"` // Second admin who approved or rejected it
+	ReviewedAt    *time.Time       `json:"reviewed_at,omitempty"`
+	ReviewNote    string           `gorm:"size:255" json:"review_note,omitempty"`
+}
+
+// CommissionStatus represents the payout state of a referral commission
+type CommissionStatus string
+
+const (
+	CommissionStatusPending CommissionStatus = "pending" // Held until the referrer claims it
+	CommissionStatusPaid    CommissionStatus = "paid"    // Credited to the referrer's balance
+)
+
+// ReferralCommission is a referrer's commission for one day (推荐佣金),
+// computed from the net loss of the players they invited. Revision 0 is the
+// first settlement; later revisions carry the difference when the day's bets
+// were voided or re-settled afterwards, so a day's totals are the sums over
+// its revisions.
+type ReferralCommission struct {
+	gorm.Model
+	ReferrerID    uint             `gorm:"uniqueIndex:idx_referral_commissions_day;not null" json:"referrer_id"`
+	Referrer      User             `gorm:"foreignKey:ReferrerID" json:"-"`
+	Date          string           `gorm:"size:10;uniqueIndex:idx_referral_commissions_day;not null" json:"date"` // YYYY-MM-DD the bets were placed
+	Revision      int              `gorm:"uniqueIndex:idx_referral_commissions_day;default:0" json:"revision"`
+	ReferralCount int              `gorm:"default:0" json:"referral_count"` // Referrals with settled bets that day
+	CustomerLoss  Money            `gorm:"not null" json:"customer_loss"`   // Stakes minus winnings; negative when they won
+	Rate          Rate             `gorm:"not null" json:"rate"`
+	Amount        Money            `gorm:"not null" json:"amount"` // Loss × rate rounded down, minus earlier revisions; may be negative in a revision
+	Status        CommissionStatus `gorm:"size:20;index;default:'pending'" json:"status"`
+	PaidAt        *time.Time       `json:"paid_at,omitempty"`
 }
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidMoney is returned for an amount that is not a decimal with at most two fraction digits
+var ErrInvalidMoney = errors.New("invalid money amount")
+
+// MoneyScale is the number of minor units (cents) in one unit of currency
+const MoneyScale = 100
+
+// Money is an amount in cents. Balances, stakes and payouts are integers so
+// sums never drift; in JSON it is written and read as a decimal number with
+// at most two fraction digits (12.34), converted exactly.
+type Money int64
+
+// ParseMoney parses a decimal amount such as "12.34", "-5" or "0.5".
+// More than two fraction digits is an error rather than silently rounded.
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" || len(frac) > 2 || !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+	for len(frac) < 2 {
+		frac += "0"
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || w > math.MaxInt64/MoneyScale-1 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+	f, err := strconv.ParseInt(frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+
+	m := Money(w*MoneyScale + f)
+	if neg {
+		m = -m
+	}
+	return m, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// MoneyFromFloat converts a float, rounding half away from zero to the cent.
+// Only for values that were never money in the first place, such as a
+// stake computed with a multiplier.
+func MoneyFromFloat(f float64) Money {
+	return Money(math.Round(f * MoneyScale))
+}
+
+// String formats the amount with exactly two fraction digits
+func (m Money) String() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/MoneyScale, v%MoneyScale)
+}
+
+// Float returns the amount in currency units, for display and statistics only
+func (m Money) Float() float64 {
+	return float64(m) / MoneyScale
+}
+
+// Mul applies a rate, rounding down to the cent (toward zero), as payouts
+// and commissions do
+func (m Money) Mul(r Rate) Money {
+	return Money(int64(m) * int64(r) / RateScale)
+}
+
+// MarshalJSON writes the amount as a JSON number
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON reads a JSON number or numeric string
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if string(data) == "null" {
+		return nil
+	}
+	v, err := ParseMoney(string(data))
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// RateScale is the number of steps in 1.0 of a Rate
+const RateScale = 10000
+
+// Rate is a fixed-point multiplier in ten-thousandths (1.98 is 19800), used
+// for the odds stored on bets and for commission rates
+type Rate int64
+
+// RateFromFloat converts a configured multiplier, rounding half away from
+// zero to the nearest ten-thousandth
+func RateFromFloat(f float64) Rate {
+	return Rate(math.Round(f * RateScale))
+}
+
+// Float returns the multiplier as a float, for display and analysis only
+func (r Rate) Float() float64 {
+	return float64(r) / RateScale
+}
+
+// MarshalJSON writes the rate as a JSON number
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(r.Float(), 'f', -1, 64)), nil
+}
+
+// UnmarshalJSON reads a JSON number
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if string(data) == "null" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return err
+	}
+	*r = RateFromFloat(f)
+	return nil
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+		err  bool
+	}{
+		{"12.34", 1234, false},
+		{"12.3", 1230, false},
+		{"12", 1200, false},
+		{"0.05", 5, false},
+		{".5", 50, false},
+		{"-5.01", -501, false},
+		{"0.1", 10, false},
+		{"1.005", 0, true},
+		{"", 0, true},
+		{".", 0, true},
+		{"abc", 0, true},
+		{"1e3", 0, true},
+		{"1.-2", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if tt.err {
+			if !errors.Is(err, ErrInvalidMoney) {
+				t.Errorf("ParseMoney(%q) error = %v, want ErrInvalidMoney", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := map[Money]string{
+		0:     "0.00",
+		5:     "0.05",
+		1234:  "12.34",
+		-501:  "-5.01",
+		-5:    "-0.05",
+		10000: "100.00",
+	}
+	for m, want := range tests {
+		if got := m.String(); got != want {
+			t.Errorf("Money(%d).String() = %q, want %q", int64(m), got, want)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var req struct {
+		Amount Money `json:"amount"`
+	}
+	if err := json.Unmarshal([]byte(`{"amount": 0.1}`), &req); err != nil || req.Amount != 10 {
+		t.Fatalf("unmarshal 0.1 = %d, %v", req.Amount, err)
+	}
+	if err := json.Unmarshal([]byte(`{"amount": 0.125}`), &req); err == nil {
+		t.Fatal("unmarshal 0.125 should fail")
+	}
+
+	data, err := json.Marshal(map[string]Money{"balance": 123456})
+	if err != nil || string(data) != `{"balance":1234.56}` {
+		t.Fatalf("marshal = %s, %v", data, err)
+	}
+}
+
+func TestMoneyMul(t *testing.T) {
+	tests := []struct {
+		amount Money
+		rate   Rate
+		want   Money
+	}{
+		{10000, RateFromFloat(1.95), 19500},
+		{333, RateFromFloat(1.95), 649},  // 6.4935 rounds down
+		{1, RateFromFloat(9.8), 9},       // 0.098 rounds down
+		{999, RateFromFloat(0.1), 99},    // 9.99 × 10% = 0.999
+		{-999, RateFromFloat(0.1), -99},  // toward zero
+		{2900, RateFromFloat(0.29), 841}, // no float drift
+	}
+	for _, tt := range tests {
+		if got := tt.amount.Mul(tt.rate); got != tt.want {
+			t.Errorf("%s.Mul(%v) = %s, want %s", tt.amount, tt.rate.Float(), got, tt.want)
+		}
+	}
+}
+
+func TestRateJSON(t *testing.T) {
+	var r Rate
+	if err := json.Unmarshal([]byte("1.98"), &r); err != nil || r != 19800 {
+		t.Fatalf("unmarshal 1.98 = %d, %v", r, err)
+	}
+	data, err := json.Marshal(r)
+	if err != nil || string(data) != "1.98" {
+		t.Fatalf("marshal = %s, %v", data, err)
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"pcgame/backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrAdjustmentNotFound   = errors.New("balance adjustment not found")
+	ErrAdjustmentNotPending = errors.New("balance adjustment has already been reviewed")
+	ErrInvalidAdjustment    = errors.New("invalid balance adjustment")
+	ErrSelfApproval         = errors.New("adjustment must be approved by a different admin")
+)
+
+// adjustmentReasons are the accepted reason codes
+var adjustmentReasons = map[model.AdjustmentReason]bool{
+	model.AdjustmentCompensation: true,
+	model.AdjustmentCorrection:   true,
+	model.AdjustmentPromotion:    true,
+	model.AdjustmentRecovery:     true,
+	model.AdjustmentOther:        true,
+}
+
+// AdjustmentRequest is a manual balance change asked for by an admin
+type AdjustmentRequest struct {
+	UserID uint
+	Amount model.Money // Signed
+	Reason model.AdjustmentReason
+	Note   string
+}
+
+// AdjustmentResult is an adjustment after a change, with the player's balance
+type AdjustmentResult struct {
+	Adjustment model.BalanceAdjustment
+	Balance    model.Money
+}
+
+// AdjustmentService applies manual balance adjustments (人工调账)
+type AdjustmentService struct {
+	db                *gorm.DB
+	approvalThreshold model.Money
+}
+
+// NewAdjustmentService creates the service; adjustments whose absolute amount
+// exceeds approvalThreshold need a second admin
+func NewAdjustmentService(db *gorm.DB, approvalThreshold model.Money) *AdjustmentService {
+	return &AdjustmentService{db: db, approvalThreshold: approvalThreshold}
+}
+
+// NeedsApproval reports whether an amount must be approved by a second admin
+func (s *AdjustmentService) NeedsApproval(amount model.Money) bool {
+	if amount < 0 {
+		amount = -amount
+	}
+	return amount > s.approvalThreshold
+}
+
+// Create records an adjustment and applies it at once unless it needs approval.
+// A debit never takes the balance below zero.
+func (s *AdjustmentService) Create(adminID uint, req AdjustmentRequest) (*AdjustmentResult, error) {
+	if req.Amount == 0 {
+		return nil, fmt.Errorf("%w: amount must not be zero", ErrInvalidAdjustment)
+	}
+	if !adjustmentReasons[req.Reason] {
+		return nil, fmt.Errorf("%w: unknown reason %q", ErrInvalidAdjustment, req.Reason)
+	}
+	if req.Note == "" {
+		return nil, fmt.Errorf("%w: note is required", ErrInvalidAdjustment)
+	}
+
+	res := AdjustmentResult{Adjustment: model.BalanceAdjustment{
+		UserID:        req.UserID,
+		Amount:        req.Amount,
+		Reason:        req.Reason,
+		Note:          req.Note,
+		Status:        model.AdjustmentStatusPending,
+		RequestedByID: adminID,
+	}}
+	adj := &res.Adjustment
+	if !s.NeedsApproval(req.Amount) {
+		adj.Status = model.AdjustmentStatusApplied
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(adj).Error; err != nil {
+			return err
+		}
+		if adj.Status == model.AdjustmentStatusApplied {
+			if err := applyAdjustment(tx, adj); err != nil {
+				return err
+			}
+		}
+		return readBalance(tx, adj.UserID, &res.Balance)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Approve applies a pending adjustment; the approver must not be its requester
+func (s *AdjustmentService) Approve(id, adminID uint, note string) (*AdjustmentResult, error) {
+	return s.review(id, adminID, model.AdjustmentStatusApplied, note)
+}
+
+// Reject declines a pending adjustment without touching the balance
+func (s *AdjustmentService) Reject(id, adminID uint, note string) (*AdjustmentResult, error) {
+	return s.review(id, adminID, model.AdjustmentStatusRejected, note)
+}
+
+func (s *AdjustmentService) review(id, adminID uint, status model.AdjustmentStatus, note string) (*AdjustmentResult, error) {
+	var res AdjustmentResult
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		adj := &res.Adjustment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(adj, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAdjustmentNotFound
+			}
+			return err
+		}
+		if adj.Status != model.AdjustmentStatusPending {
+			return ErrAdjustmentNotPending
+		}
+		if status == model.AdjustmentStatusApplied {
+			if adj.RequestedByID == adminID {
+				return ErrSelfApproval
+			}
+			if err := applyAdjustment(tx, adj); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		adj.Status = status
+		adj.ReviewedByID = &adminID
+		adj.ReviewedAt = &now
+		adj.ReviewNote = note
+		if err := tx.Model(adj).Updates(map[string]interface{}{
+			"status":         status,
+			"reviewed_by_id": adminID,
+			"reviewed_at":    now,
+			"review_note":    note,
+		}).Error; err != nil {
+			return err
+		}
+		return readBalance(tx, adj.UserID, &res.Balance)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// applyAdjustment posts an adjustment to the balance and the ledger
+func applyAdjustment(tx *gorm.DB, adj *model.BalanceAdjustment) error {
+	entry := LedgerEntry{
+		Type:    model.WalletTxAdjustment,
+		RefType: "adjustment",
+		RefID:   &adj.ID,
+		Note:    string(adj.Reason) + ": " + adj.Note,
+	}
+	if adj.Amount > 0 {
+		return Credit(tx, adj.UserID, adj.Amount, entry)
+	}
+	return Debit(tx, adj.UserID, -adj.Amount, entry)
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"pcgame/backend/internal/model"
+)
+
+func TestAdjustmentNeedsApproval(t *testing.T) {
+	s := NewAdjustmentService(nil, 1000*model.MoneyScale)
+
+	tests := []struct {
+		amount model.Money
+		want   bool
+	}{
+		{1, false},
+		{-1, false},
+		{1000 * model.MoneyScale, false},
+		{-1000 * model.MoneyScale, false},
+		{1000*model.MoneyScale + 1, true},
+		{-1000*model.MoneyScale - 1, true},
+	}
+
+	for _, tt := range tests {
+		if got := s.NeedsApproval(tt.amount); got != tt.want {
+			t.Errorf("NeedsApproval(%s) = %v, want %v", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestAdjustmentCreateValidatesWithoutDB(t *testing.T) {
+	// Invalid requests are refused before any query is made
+	s := NewAdjustmentService(nil, 1000*model.MoneyScale)
+
+	tests := []AdjustmentRequest{
+		{UserID: 1, Amount: 0, Reason: model.AdjustmentCompensation, Note: "x"},
+		{UserID: 1, Amount: 100, Reason: "gift", Note: "x"},
+		{UserID: 1, Amount: 100, Reason: model.AdjustmentCorrection, Note: ""},
+	}
+
+	for _, req := range tests {
+		if _, err := s.Create(1, req); !errors.Is(err, ErrInvalidAdjustment) {
+			t.Errorf("Create(%+v) error = %v, want ErrInvalidAdjustment", req, err)
+		}
+	}
+}
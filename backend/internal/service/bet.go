@@ -0,0 +1,297 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"pcgame/backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidBet          = errors.New("invalid bet")
+	ErrBettingClosed       = errors.New("betting has closed for this round")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrBetSlipRejected     = errors.New("bet slip rejected")
+	ErrBetNotFound         = errors.New("bet not found")
+	ErrBetNotCancellable   = errors.New("bet can no longer be cancelled")
+	ErrBetCancelDisabled   = errors.New("bet cancellation is disabled")
+	ErrCancelLimitReached  = errors.New("daily cancellation limit reached")
+)
+
+// MaxBetSlipLines caps the number of bets in one bet slip
+const MaxBetSlipLines = 50
+
+// BetLineError explains why one line of a bet slip was rejected
+type BetLineError struct {
+	Index int    `json:"index"` // 0-based position in the slip
+	Error string `json:"error"`
+}
+
+// BetRequest is one bet to be placed on a round
+type BetRequest struct {
+	RoundID  uint
+	Room     string // Optional; must match the round's room when set
+	BetType  string
+	BetValue int
+	Amount   model.Money
+}
+
+// BetService validates and places bets. Every way a bet enters the system
+// goes through it, so the same rules and balance handling apply everywhere.
+type BetService struct {
+	db          *gorm.DB
+	roomSvc     *RoomService
+	settingsSvc *SettingsService
+}
+
+func NewBetService(db *gorm.DB) *BetService {
+	return &BetService{
+		db:          db,
+		roomSvc:     NewRoomService(db),
+		settingsSvc: NewSettingsService(db),
+	}
+}
+
+// acceptsBets reports whether a round in this state takes bets; scheduled
+// (pending) rounds accept bets in advance
+func acceptsBets(status model.RoundStatus) bool {
+	return status == model.RoundStatusOpen || status == model.RoundStatusPending
+}
+
+// canCancelBet reports whether a bet on the round may still be withdrawn at
+// now: under the same rule as placing it, before the bet deadline
+func canCancelBet(round *model.PC28Round, settings *GameSettings, now time.Time) bool {
+	return acceptsBets(round.Status) && now.Before(settings.BetDeadline(round.CloseTime))
+}
+
+// Place places a bet paid from the player's balance
+func (s *BetService) Place(userID uint, req BetRequest) (*model.PC28Bet, error) {
+	var bet *model.PC28Bet
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		bet, err = s.PlaceTx(tx, userID, req, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bet, nil
+}
+
+// PlaceTx places a bet inside the caller's transaction. A bet of a chase
+// plan is paid from the plan's reserved funds, so the balance is untouched.
+func (s *BetService) PlaceTx(tx *gorm.DB, userID uint, req BetRequest, chasePlanID *uint) (*model.PC28Bet, error) {
+	bet, err := s.CheckTx(tx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	bet.UserID = userID
+	bet.ChasePlanID = chasePlanID
+	if err := tx.Create(bet).Error; err != nil {
+		return nil, err
+	}
+
+	if chasePlanID == nil {
+		if err := Debit(tx, userID, bet.Amount, betEntry(model.WalletTxBet, bet)); err != nil {
+			return nil, err
+		}
+	}
+	return bet, nil
+}
+
+// PlaceBatch places a bet slip atomically: every line is validated first,
+// then all bets are created and debited, so either all succeed or none does.
+// When lines are rejected it returns ErrBetSlipRejected with the reasons.
+func (s *BetService) PlaceBatch(userID uint, reqs []BetRequest) ([]model.PC28Bet, []BetLineError, error) {
+	if len(reqs) == 0 || len(reqs) > MaxBetSlipLines {
+		return nil, nil, fmt.Errorf("%w: a bet slip takes 1 to %d bets", ErrInvalidBet, MaxBetSlipLines)
+	}
+
+	var bets []model.PC28Bet
+	var rejected []BetLineError
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for i, req := range reqs {
+			bet, err := s.CheckTx(tx, req)
+			if err != nil {
+				if !errors.Is(err, ErrRoundNotFound) && !errors.Is(err, ErrInvalidBet) && !errors.Is(err, ErrBettingClosed) {
+					return err
+				}
+				rejected = append(rejected, BetLineError{Index: i, Error: err.Error()})
+				continue
+			}
+			bet.UserID = userID
+			bets = append(bets, *bet)
+		}
+		if len(rejected) > 0 {
+			return ErrBetSlipRejected
+		}
+
+		if err := tx.Create(&bets).Error; err != nil {
+			return err
+		}
+		// Fails as a whole if the slip's total exceeds the balance
+		for i := range bets {
+			if err := Debit(tx, userID, bets[i].Amount, betEntry(model.WalletTxBet, &bets[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, rejected, err
+	}
+	return bets, nil, nil
+}
+
+// Cancel withdraws a player's pending bet while its round is open and before
+// the cutoff, refunding the stake in the same transaction. Returns the bet
+// and the player's new balance.
+func (s *BetService) Cancel(userID, betID uint) (*model.PC28Bet, model.Money, error) {
+	var bet model.PC28Bet
+	var user model.User
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// The user lock serializes this player's cancellations for the daily limit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", betID, userID).First(&bet).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrBetNotFound
+			}
+			return err
+		}
+		if bet.Status != model.BetStatusPending {
+			return ErrBetNotCancellable
+		}
+		if bet.ChasePlanID != nil {
+			return fmt.Errorf("%w: cancel the chase plan instead", ErrBetNotCancellable)
+		}
+
+		var round model.PC28Round
+		if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).First(&round, bet.RoundID).Error; err != nil {
+			return err
+		}
+		room, _, err := s.roomSvc.GameForRound(&round)
+		if err != nil {
+			return err
+		}
+		settings, err := s.settingsSvc.GetForRoom(room)
+		if err != nil {
+			return err
+		}
+
+		if !settings.BetCancel {
+			return ErrBetCancelDisabled
+		}
+		now := time.Now()
+		if !canCancelBet(&round, settings, now) {
+			return ErrBetNotCancellable
+		}
+
+		if settings.CancelLimit > 0 {
+			dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+			var count int64
+			if err := tx.Model(&model.PC28Bet{}).
+				Where("user_id = ? AND status = ? AND cancelled_at >= ?", userID, model.BetStatusCancelled, dayStart).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count >= int64(settings.CancelLimit) {
+				return fmt.Errorf("%w: %d per day", ErrCancelLimitReached, settings.CancelLimit)
+			}
+		}
+
+		if err := tx.Model(&bet).Updates(map[string]interface{}{
+			"status":       model.BetStatusCancelled,
+			"cancelled_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		bet.Status = model.BetStatusCancelled
+		bet.CancelledAt = &now
+
+		if err := Credit(tx, userID, bet.Amount, betEntry(model.WalletTxBetCancel, &bet)); err != nil {
+			return err
+		}
+		return tx.Select("balance").First(&user, userID).Error
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return &bet, user.Balance, nil
+}
+
+// betEntry is the ledger entry of a change caused by one bet
+func betEntry(txType model.WalletTxType, bet *model.PC28Bet) LedgerEntry {
+	return LedgerEntry{Type: txType, BetID: &bet.ID, RoundID: &bet.RoundID}
+}
+
+// CheckTx validates a bet against its round, game and settings and returns
+// the unsaved bet with its odds. The round row is share-locked for the rest
+// of the transaction so it cannot close, void or settle underneath the bet,
+// while other bets on it still run concurrently.
+func (s *BetService) CheckTx(tx *gorm.DB, req BetRequest) (*model.PC28Bet, error) {
+	var round model.PC28Round
+	if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).First(&round, req.RoundID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoundNotFound
+		}
+		return nil, err
+	}
+
+	if !acceptsBets(round.Status) {
+		return nil, fmt.Errorf("%w: round is not accepting bets", ErrBettingClosed)
+	}
+
+	room, game, err := s.roomSvc.GameForRound(&round)
+	if err != nil {
+		return nil, err
+	}
+	if req.Room != "" && req.Room != room.Code {
+		return nil, fmt.Errorf("%w: round does not belong to this room", ErrInvalidBet)
+	}
+
+	if err := game.ValidateBet(req.BetType, req.BetValue); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBet, err)
+	}
+
+	settings, err := s.settingsSvc.GetForRoom(room)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Amount < settings.MinBet || req.Amount > settings.MaxBet {
+		return nil, fmt.Errorf("%w: bet amount must be between %s and %s", ErrInvalidBet, settings.MinBet, settings.MaxBet)
+	}
+
+	odds := game.BetOdds(settings, req.BetType, req.BetValue)
+	if odds == 0 {
+		return nil, fmt.Errorf("%w: invalid bet type", ErrInvalidBet)
+	}
+
+	if !time.Now().Before(settings.BetDeadline(round.CloseTime)) {
+		return nil, ErrBettingClosed
+	}
+
+	return &model.PC28Bet{
+		RoundID:  round.ID,
+		BetType:  model.BetType(req.BetType),
+		BetValue: req.BetValue,
+		Amount:   req.Amount,
+		Odds:     model.RateFromFloat(odds),
+		Status:   model.BetStatusPending,
+	}, nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"pcgame/backend/internal/model"
+)
+
+func TestCanCancelBet(t *testing.T) {
+	settings := NewGameService().DefaultGameSettings()
+	closeTime := time.Date(2026, 1, 22, 12, 0, 0, 0, time.UTC)
+	deadline := settings.BetDeadline(closeTime)
+
+	tests := []struct {
+		name   string
+		status model.RoundStatus
+		now    time.Time
+		want   bool
+	}{
+		{"pending round far from deadline", model.RoundStatusPending, closeTime.Add(-time.Hour), true},
+		{"open round before deadline", model.RoundStatusOpen, deadline.Add(-time.Second), true},
+		{"open round at deadline", model.RoundStatusOpen, deadline, false},
+		{"pending round past deadline", model.RoundStatusPending, deadline.Add(time.Second), false},
+		{"closed round", model.RoundStatusClosed, closeTime.Add(-time.Hour), false},
+		{"void round", model.RoundStatusVoid, closeTime.Add(-time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		round := &model.PC28Round{Status: tt.status, CloseTime: closeTime}
+		if got := canCancelBet(round, settings, tt.now); got != tt.want {
+			t.Errorf("%s: canCancelBet() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
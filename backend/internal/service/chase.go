@@ -0,0 +1,312 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"pcgame/backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MaxChaseRounds caps how many rounds one chase plan may cover
+const MaxChaseRounds = 100
+
+var (
+	ErrChaseNotFound  = errors.New("chase plan not found")
+	ErrChaseNotActive = errors.New("chase plan is not active")
+	ErrInvalidChase   = errors.New("invalid chase plan")
+)
+
+// ChaseRequest describes a new chase plan
+type ChaseRequest struct {
+	Room       string
+	BetType    string
+	BetValue   int
+	Amount     model.Money // Stake of the first round
+	Rounds     int
+	Multiplier float64 // Zero means a flat stake
+	StopOnWin  bool
+}
+
+// ChaseService manages chase plans (追号). The whole plan is paid up front;
+// each round's bet is then placed from the reservation through BetService
+// when the round opens.
+type ChaseService struct {
+	db          *gorm.DB
+	betSvc      *BetService
+	roomSvc     *RoomService
+	settingsSvc *SettingsService
+}
+
+func NewChaseService(db *gorm.DB) *ChaseService {
+	return &ChaseService{
+		db:          db,
+		betSvc:      NewBetService(db),
+		roomSvc:     NewRoomService(db),
+		settingsSvc: NewSettingsService(db),
+	}
+}
+
+// ChaseStake returns the stake of the i-th (0-based) round of a plan,
+// rounded half away from zero to the cent. A stake beyond the range of
+// Money saturates instead of wrapping, so limit checks still reject it.
+func ChaseStake(base model.Money, multiplier float64, i int) model.Money {
+	stake := math.Round(float64(base) * math.Pow(multiplier, float64(i)))
+	switch {
+	case math.IsNaN(stake) || stake >= math.MaxInt64: // float64(MaxInt64) is 2^63
+		return math.MaxInt64
+	case stake <= math.MinInt64:
+		return math.MinInt64
+	}
+	return model.Money(stake)
+}
+
+// ChaseReservation checks that every stake of a plan is within the bet
+// limits and returns their total, the amount reserved up front
+func ChaseReservation(base model.Money, multiplier float64, rounds int, minBet, maxBet model.Money) (model.Money, error) {
+	var reserved model.Money
+	for i := 0; i < rounds; i++ {
+		stake := ChaseStake(base, multiplier, i)
+		if stake < minBet || stake > maxBet {
+			return 0, fmt.Errorf("%w: every stake must be between %s and %s", ErrInvalidBet, minBet, maxBet)
+		}
+		if reserved > math.MaxInt64-stake {
+			return 0, fmt.Errorf("%w: total stake is too large", ErrInvalidChase)
+		}
+		reserved += stake
+	}
+	if reserved <= 0 {
+		return 0, fmt.Errorf("%w: total stake must be positive", ErrInvalidChase)
+	}
+	return reserved, nil
+}
+
+// Create validates a plan and reserves the stakes of all its rounds
+func (s *ChaseService) Create(userID uint, req ChaseRequest) (*model.ChasePlan, error) {
+	if req.Multiplier == 0 {
+		req.Multiplier = 1
+	}
+	if req.Rounds < 1 || req.Rounds > MaxChaseRounds {
+		return nil, fmt.Errorf("%w: rounds must be between 1 and %d", ErrInvalidChase, MaxChaseRounds)
+	}
+	if req.Multiplier < 1 || req.Multiplier > 10 {
+		return nil, fmt.Errorf("%w: multiplier must be between 1 and 10", ErrInvalidChase)
+	}
+
+	room, err := s.roomSvc.GetByCode(req.Room)
+	if err != nil {
+		return nil, err
+	}
+	game, err := GetGame(room.GameCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := game.ValidateBet(req.BetType, req.BetValue); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBet, err)
+	}
+
+	settings, err := s.settingsSvc.GetForRoom(room)
+	if err != nil {
+		return nil, err
+	}
+	if game.BetOdds(settings, req.BetType, req.BetValue) == 0 {
+		return nil, fmt.Errorf("%w: invalid bet type", ErrInvalidBet)
+	}
+
+	reserved, err := ChaseReservation(req.Amount, req.Multiplier, req.Rounds, settings.MinBet, settings.MaxBet)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := model.ChasePlan{
+		UserID:      userID,
+		RoomID:      room.ID,
+		BetType:     model.BetType(req.BetType),
+		BetValue:    req.BetValue,
+		BaseAmount:  req.Amount,
+		Multiplier:  req.Multiplier,
+		TotalRounds: req.Rounds,
+		StopOnWin:   req.StopOnWin,
+		Reserved:    reserved,
+		Status:      model.ChaseStatusActive,
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&plan).Error; err != nil {
+			return err
+		}
+		return Debit(tx, userID, reserved, planEntry(model.WalletTxChaseReserve, &plan))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// Cancel stops a player's active plan and refunds its unplaced stakes
+func (s *ChaseService) Cancel(userID, planID uint) (*model.ChasePlan, error) {
+	var plan model.ChasePlan
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", planID, userID).First(&plan).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrChaseNotFound
+			}
+			return err
+		}
+		if plan.Status != model.ChaseStatusActive {
+			return ErrChaseNotActive
+		}
+		return s.endTx(tx, &plan, model.ChaseStatusCancelled, "Cancelled by player")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// PlaceForRound places the next bet of every active plan in the round's room.
+// Each plan is handled in its own transaction; a plan whose bet is rejected
+// is stopped and refunded. Returns the number of bets placed.
+func (s *ChaseService) PlaceForRound(round *model.PC28Round) (int, error) {
+	var ids []uint
+	if err := s.db.Model(&model.ChasePlan{}).
+		Where("room_id = ? AND status = ? AND last_round_id < ?", round.RoomID, model.ChaseStatusActive, round.ID).
+		Order("id asc").
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+
+	placed := 0
+	var errs []error
+	for _, id := range ids {
+		ok, err := s.placeNext(id, round)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("chase plan %d: %w", id, err))
+			continue
+		}
+		if ok {
+			placed++
+		}
+	}
+	return placed, errors.Join(errs...)
+}
+
+// placeNext places one plan's bet on a round, at most once per round
+func (s *ChaseService) placeNext(planID uint, round *model.PC28Round) (bool, error) {
+	placed := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var plan model.ChasePlan
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&plan, planID).Error; err != nil {
+			return err
+		}
+		if plan.Status != model.ChaseStatusActive || plan.LastRoundID >= round.ID {
+			return nil
+		}
+
+		// A win the settlement hook has not seen yet still stops the plan
+		if plan.StopOnWin {
+			var won int64
+			if err := tx.Model(&model.PC28Bet{}).
+				Where("chase_plan_id = ? AND status = ?", plan.ID, model.BetStatusWon).
+				Count(&won).Error; err != nil {
+				return err
+			}
+			if won > 0 {
+				return s.endTx(tx, &plan, model.ChaseStatusStopped, "Stopped after a win")
+			}
+		}
+
+		stake := ChaseStake(plan.BaseAmount, plan.Multiplier, plan.PlacedRounds)
+		_, err := s.betSvc.PlaceTx(tx.SavePoint("chase_bet"), plan.UserID, BetRequest{
+			RoundID:  round.ID,
+			BetType:  string(plan.BetType),
+			BetValue: plan.BetValue,
+			Amount:   stake,
+		}, &plan.ID)
+		if err != nil {
+			if errors.Is(err, ErrInvalidBet) || errors.Is(err, ErrBettingClosed) {
+				if err := tx.RollbackTo("chase_bet").Error; err != nil {
+					return err
+				}
+				return s.endTx(tx, &plan, model.ChaseStatusStopped,
+					fmt.Sprintf("Bet rejected in round %s: %v", round.IssueNumber, err))
+			}
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"placed_rounds": gorm.Expr("placed_rounds + 1"),
+			"spent":         gorm.Expr("spent + ?", stake),
+			"last_round_id": round.ID,
+		}
+		if plan.PlacedRounds+1 >= plan.TotalRounds {
+			updates["status"] = model.ChaseStatusCompleted
+		}
+		if err := tx.Model(&plan).Updates(updates).Error; err != nil {
+			return err
+		}
+		placed = true
+		return nil
+	})
+	return placed, err
+}
+
+// StopWonPlans stops the stop-on-win plans that won in a settled round and
+// refunds their unplaced stakes. Returns the number of plans stopped.
+func (s *ChaseService) StopWonPlans(round *model.PC28Round) (int, error) {
+	var ids []uint
+	if err := s.db.Model(&model.ChasePlan{}).
+		Where("status = ? AND stop_on_win = ?", model.ChaseStatusActive, true).
+		Where("id IN (?)", s.db.Model(&model.PC28Bet{}).Select("chase_plan_id").
+			Where("round_id = ? AND status = ? AND chase_plan_id IS NOT NULL", round.ID, model.BetStatusWon)).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+
+	stopped := 0
+	for _, id := range ids {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var plan model.ChasePlan
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&plan, id).Error; err != nil {
+				return err
+			}
+			if plan.Status != model.ChaseStatusActive {
+				return nil
+			}
+			stopped++
+			return s.endTx(tx, &plan, model.ChaseStatusStopped, "Won in round "+round.IssueNumber)
+		})
+		if err != nil {
+			return stopped, err
+		}
+	}
+	return stopped, nil
+}
+
+// endTx finishes a locked plan and credits back what was never placed
+func (s *ChaseService) endTx(tx *gorm.DB, plan *model.ChasePlan, status model.ChaseStatus, reason string) error {
+	refund := plan.Reserved - plan.Spent - plan.Refunded
+	if refund > 0 {
+		if err := Credit(tx, plan.UserID, refund, planEntry(model.WalletTxChaseRefund, plan)); err != nil {
+			return err
+		}
+	}
+
+	plan.Status = status
+	plan.StopReason = reason
+	plan.Refunded += refund
+	return tx.Model(plan).Updates(map[string]interface{}{
+		"status":      status,
+		"stop_reason": reason,
+		"refunded":    plan.Refunded,
+	}).Error
+}
+
+// planEntry is the ledger entry of a change caused by a chase plan
+func planEntry(txType model.WalletTxType, plan *model.ChasePlan) LedgerEntry {
+	return LedgerEntry{Type: txType, RefType: "chase_plan", RefID: &plan.ID}
+}
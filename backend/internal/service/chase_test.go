@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"pcgame/backend/internal/model"
+)
+
+func TestChaseStake(t *testing.T) {
+	tests := []struct {
+		base       model.Money
+		multiplier float64
+		i          int
+		want       model.Money
+	}{
+		{1000, 1, 0, 1000},
+		{1000, 1, 9, 1000},
+		{1000, 2, 0, 1000},
+		{1000, 2, 3, 8000},
+		{100, 1.5, 2, 225},
+		{333, 1.1, 1, 366}, // 3.663 rounds to cents
+		{105, 1.5, 1, 158}, // 1.575 rounds half away from zero
+	}
+	for _, tt := range tests {
+		if got := ChaseStake(tt.base, tt.multiplier, tt.i); got != tt.want {
+			t.Errorf("ChaseStake(%v, %v, %d) = %v, want %v", tt.base, tt.multiplier, tt.i, got, tt.want)
+		}
+	}
+}
+
+func TestChaseStakeSaturates(t *testing.T) {
+	// 200 × 4.9^25 is far beyond int64 cents; it must not wrap negative
+	if got := ChaseStake(200, 4.9, 25); got != math.MaxInt64 {
+		t.Errorf("ChaseStake(200, 4.9, 25) = %d, want MaxInt64", int64(got))
+	}
+	if got := ChaseStake(100, 10, 99); got != math.MaxInt64 {
+		t.Errorf("ChaseStake(100, 10, 99) = %d, want MaxInt64", int64(got))
+	}
+}
+
+func TestChaseReservation(t *testing.T) {
+	const (
+		minBet = 1 * model.MoneyScale
+		maxBet = 100000 * model.MoneyScale
+	)
+
+	tests := []struct {
+		name       string
+		base       model.Money
+		multiplier float64
+		rounds     int
+		want       model.Money
+		wantErr    error
+	}{
+		{"flat", 1000, 1, 10, 10000, nil},
+		{"doubling", 100, 2, 4, 1500, nil},
+		{"below min", 50, 1, 3, 0, ErrInvalidBet},
+		{"last above max", 1000, 10, 10, 0, ErrInvalidBet},
+		{"overflowing stake", 200, 4.9, 26, 0, ErrInvalidBet},
+		{"huge multiplier", 100, 10, MaxChaseRounds, 0, ErrInvalidBet},
+		{"no rounds", 1000, 1, 0, 0, ErrInvalidChase},
+	}
+
+	for _, tt := range tests {
+		got, err := ChaseReservation(tt.base, tt.multiplier, tt.rounds, minBet, maxBet)
+		if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: reserved = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	// Limits wide enough for every stake still cannot overflow the total
+	if _, err := ChaseReservation(math.MaxInt64/2, 1, 3, 1, math.MaxInt64); !errors.Is(err, ErrInvalidChase) {
+		t.Errorf("overflowing total: error = %v, want ErrInvalidChase", err)
+	}
+}
+
+func TestLedgerRefusesNegativeAmounts(t *testing.T) {
+	// Refused before any query is made
+	if err := Debit(nil, 1, -100, LedgerEntry{}); !errors.Is(err, ErrNegativeAmount) {
+		t.Errorf("Debit(-100) error = %v, want ErrNegativeAmount", err)
+	}
+	if err := Credit(nil, 1, -100, LedgerEntry{}); !errors.Is(err, ErrNegativeAmount) {
+		t.Errorf("Credit(-100) error = %v, want ErrNegativeAmount", err)
+	}
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"pcgame/backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrNoPendingCommission = errors.New("no pending commission to claim")
+
+// finalBetStatuses are the bets whose stake counts toward customer loss
+var finalBetStatuses = []model.BetStatus{model.BetStatusWon, model.BetStatusLost}
+
+// CommissionDateLayout is the format of ReferralCommission.Date
+const CommissionDateLayout = "2006-01-02"
+
+// CommissionResult is a newly settled commission, with the referrer's balance
+type CommissionResult struct {
+	Commission model.ReferralCommission
+	Balance    model.Money
+	Credited   bool // Paid to the balance when it was settled
+}
+
+// ClaimResult is the outcome of claiming pending commissions
+type ClaimResult struct {
+	Claimed model.Money
+	Count   int
+	Balance model.Money
+}
+
+// CommissionService settles and pays referral commissions (推荐佣金)
+type CommissionService struct {
+	db         *gorm.DB
+	rate       model.Rate
+	autoCredit bool
+}
+
+// NewCommissionService creates the service; with autoCredit false, settled
+// commissions stay pending until the referrer claims them
+func NewCommissionService(db *gorm.DB, rate model.Rate, autoCredit bool) *CommissionService {
+	return &CommissionService{db: db, rate: rate, autoCredit: autoCredit}
+}
+
+// Rate returns the commission rate applied to new settlements
+func (s *CommissionService) Rate() model.Rate {
+	return s.rate
+}
+
+// CommissionFor is the commission on a day's customer loss, rounded down to
+// the cent; a day the referrals won pays nothing
+func CommissionFor(loss model.Money, rate model.Rate) model.Money {
+	if loss <= 0 {
+		return 0
+	}
+	return loss.Mul(rate)
+}
+
+// SettleDay records each referrer's commission for the bets their referrals
+// placed on day (local time) and, with auto credit, pays it. A referrer is
+// only settled once every such bet is final (won or lost, or refunded and
+// cancelled, which do not count). Running it again compares the day's loss
+// with what was recorded: when bets were voided or re-settled since, it adds
+// a revision carrying the difference, which may be negative, so re-running
+// never pays twice and later changes are not lost.
+func (s *CommissionService) SettleDay(day time.Time) ([]CommissionResult, error) {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	end := start.AddDate(0, 0, 1)
+	date := start.Format(CommissionDateLayout)
+
+	type referrerLoss struct {
+		ReferrerID    uint
+		ReferralCount int
+		TotalBet      model.Money
+		TotalWin      model.Money
+		PendingBets   int
+	}
+	var losses []referrerLoss
+	err := s.db.Model(&model.PC28Bet{}).
+		Select("users.referrer_id, "+
+			"COUNT(DISTINCT CASE WHEN pc28_bets.status IN ? THEN pc28_bets.user_id END) AS referral_count, "+
+			"COALESCE(SUM(CASE WHEN pc28_bets.status IN ? THEN pc28_bets.amount ELSE 0 END), 0)::BIGINT AS total_bet, "+
+			"COALESCE(SUM(CASE WHEN pc28_bets.status = ? THEN pc28_bets.win_amount ELSE 0 END), 0)::BIGINT AS total_win, "+
+			"COUNT(CASE WHEN pc28_bets.status = ? THEN 1 END) AS pending_bets",
+			finalBetStatuses, finalBetStatuses, model.BetStatusWon, model.BetStatusPending).
+		Joins("JOIN users ON users.id = pc28_bets.user_id").
+		Where("users.referrer_id IS NOT NULL").
+		Where("pc28_bets.status IN ?", []model.BetStatus{model.BetStatusWon, model.BetStatusLost, model.BetStatusPending}).
+		Where("pc28_bets.created_at >= ? AND pc28_bets.created_at < ?", start, end).
+		Group("users.referrer_id").
+		Scan(&losses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// What has been recorded for the day so far, summed over revisions
+	type recorded struct {
+		ReferrerID   uint
+		CustomerLoss model.Money
+		Amount       model.Money
+		Revision     int
+	}
+	var records []recorded
+	err = s.db.Model(&model.ReferralCommission{}).
+		Select("referrer_id, SUM(customer_loss)::BIGINT AS customer_loss, SUM(amount)::BIGINT AS amount, "+
+			"MAX(revision) AS revision").
+		Where("date = ?", date).
+		Group("referrer_id").
+		Scan(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[uint]referrerLoss, len(losses))
+	for _, l := range losses {
+		current[l.ReferrerID] = l
+	}
+	previous := make(map[uint]recorded, len(records))
+	referrers := make([]uint, 0, len(losses)+len(records))
+	for _, l := range losses {
+		referrers = append(referrers, l.ReferrerID)
+	}
+	for _, r := range records {
+		previous[r.ReferrerID] = r
+		if _, ok := current[r.ReferrerID]; !ok {
+			referrers = append(referrers, r.ReferrerID) // Every counted bet was since voided
+		}
+	}
+
+	results := make([]CommissionResult, 0, len(referrers))
+	for _, referrerID := range referrers {
+		cur := current[referrerID]
+		if cur.PendingBets > 0 {
+			continue // Settled on a later run, once the day's bets are final
+		}
+
+		prev, seen := previous[referrerID]
+		loss := cur.TotalBet - cur.TotalWin
+		com := model.ReferralCommission{
+			ReferrerID:    referrerID,
+			Date:          date,
+			ReferralCount: cur.ReferralCount,
+			CustomerLoss:  loss - prev.CustomerLoss,
+			Rate:          s.rate,
+			Amount:        CommissionFor(loss, s.rate) - prev.Amount,
+			Status:        model.CommissionStatusPending,
+		}
+		if seen {
+			if com.CustomerLoss == 0 && com.Amount == 0 {
+				continue // Unchanged since it was recorded
+			}
+			com.Revision = prev.Revision + 1
+		}
+
+		res, err := s.record(com)
+		if err != nil {
+			return results, err
+		}
+		if res != nil {
+			results = append(results, *res)
+		}
+	}
+	return results, nil
+}
+
+// record stores a new commission or revision and, with auto credit, pays it.
+// It returns nil when another run stored the same revision first.
+func (s *CommissionService) record(com model.ReferralCommission) (*CommissionResult, error) {
+	res := CommissionResult{Commission: com}
+	c := &res.Commission
+	if c.Amount == 0 {
+		c.Status = model.CommissionStatusPaid // Nothing is owed
+	}
+
+	created := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(c)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		created = true
+
+		if c.Amount != 0 && s.autoCredit {
+			if err := payCommission(tx, c); err != nil {
+				return err
+			}
+			res.Credited = true
+		}
+		return readBalance(tx, c.ReferrerID, &res.Balance)
+	})
+	if err != nil || !created {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Claim pays all of a referrer's pending commissions to their balance. A
+// negative revision waiting among them is netted off; when the total is not
+// positive nothing is paid and the records stay pending.
+func (s *CommissionService) Claim(userID uint) (*ClaimResult, error) {
+	var res ClaimResult
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var pending []model.ReferralCommission
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("referrer_id = ? AND status = ?", userID, model.CommissionStatusPending).
+			Order("date asc, revision asc").
+			Find(&pending).Error; err != nil {
+			return err
+		}
+		for _, com := range pending {
+			res.Claimed += com.Amount
+		}
+		if len(pending) == 0 || res.Claimed <= 0 {
+			return ErrNoPendingCommission
+		}
+
+		entry := LedgerEntry{
+			Type:    model.WalletTxCommission,
+			RefType: "commission",
+			Note:    pending[0].Date + " ~ " + pending[len(pending)-1].Date,
+		}
+		if len(pending) == 1 {
+			entry.RefID = &pending[0].ID
+			entry.Note = pending[0].Date
+		}
+		if err := Credit(tx, userID, res.Claimed, entry); err != nil {
+			return err
+		}
+
+		ids := make([]uint, len(pending))
+		for i := range pending {
+			ids[i] = pending[i].ID
+		}
+		if err := tx.Model(&model.ReferralCommission{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":  model.CommissionStatusPaid,
+			"paid_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		res.Count = len(pending)
+		return readBalance(tx, userID, &res.Balance)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// payCommission posts a commission or revision and marks it paid in the
+// caller's transaction. A negative revision takes back commission already
+// paid and may leave the balance negative, like a payout correction.
+func payCommission(tx *gorm.DB, com *model.ReferralCommission) error {
+	entry := LedgerEntry{
+		Type:    model.WalletTxCommission,
+		RefType: "commission",
+		RefID:   &com.ID,
+		Note:    com.Date,
+	}
+	if com.Revision > 0 {
+		entry.Note = fmt.Sprintf("%s revision %d", com.Date, com.Revision)
+	}
+	var err error
+	if com.Amount >= 0 {
+		err = Credit(tx, com.ReferrerID, com.Amount, entry)
+	} else {
+		err = Adjust(tx, com.ReferrerID, com.Amount, entry)
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	com.Status = model.CommissionStatusPaid
+	com.PaidAt = &now
+	return tx.Model(com).Updates(map[string]interface{}{
+		"status":  com.Status,
+		"paid_at": now,
+	}).Error
+}
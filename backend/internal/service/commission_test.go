@@ -0,0 +1,28 @@
+package service
+
+import (
+	"testing"
+
+	"pcgame/backend/internal/model"
+)
+
+func TestCommissionFor(t *testing.T) {
+	rate := model.RateFromFloat(0.1)
+
+	tests := []struct {
+		loss model.Money
+		want model.Money
+	}{
+		{100000, 10000}, // 1000.00 -> 100.00
+		{12345, 1234},   // 123.45 -> 12.345, rounded down
+		{9, 0},          // 0.09 -> 0.009
+		{0, 0},
+		{-50000, 0}, // Referrals won: nothing is owed
+	}
+
+	for _, tt := range tests {
+		if got := CommissionFor(tt.loss, rate); got != tt.want {
+			t.Errorf("CommissionFor(%s) = %s, want %s", tt.loss, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"pcgame/backend/internal/model"
+)
+
+// ErrUnknownGame is returned when no registered game matches a code
+var ErrUnknownGame = errors.New("unknown game")
+
+// DrawResult is a game's result derived from the 20 drawn Keno numbers
+// A, B, C and Sum are stored on the round; games that do not use them leave them zero
+type DrawResult struct {
+	Numbers []int `json:"numbers"` // Drawn numbers, ascending
+	A       int   `json:"a"`
+	B       int   `json:"b"`
+	C       int   `json:"c"`
+	Sum     int   `json:"sum"`
+}
+
+// Game is a Keno-derived game. The scheduler draws Keno numbers for every
+// room; the room's game turns them into a result, validates and prices bets
+// and decides which bets win.
+type Game interface {
+	Code() string
+	Name() string
+	// Result derives the game result from the drawn Keno numbers
+	Result(kenoData []int) DrawResult
+	// ValidateBet checks the bet type and value
+	ValidateBet(betType string, betValue int) error
+	// CheckWin reports whether a bet wins against a result
+	CheckWin(betType string, betValue int, result DrawResult) bool
+	// DefaultSettings returns the built-in cadence, limits and odds
+	DefaultSettings() *GameSettings
+	// BetOdds returns the odds of a bet under the given settings, or 0 if invalid
+	BetOdds(settings *GameSettings, betType string, betValue int) float64
+}
+
+var (
+	gamesMu sync.RWMutex
+	games   = make(map[string]Game)
+)
+
+func init() {
+	RegisterGame(NewPC28Game())
+}
+
+// RegisterGame makes a game available to rooms; it panics on a duplicate code
+func RegisterGame(g Game) {
+	gamesMu.Lock()
+	defer gamesMu.Unlock()
+
+	if _, ok := games[g.Code()]; ok {
+		panic(fmt.Sprintf("game %s registered twice", g.Code()))
+	}
+	games[g.Code()] = g
+}
+
+// GetGame returns a registered game; an empty code means PC28
+func GetGame(code string) (Game, error) {
+	if code == "" {
+		code = model.GameCodePC28
+	}
+
+	gamesMu.RLock()
+	defer gamesMu.RUnlock()
+
+	g, ok := games[code]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownGame, code)
+	}
+	return g, nil
+}
+
+// RegisteredGames returns all registered games ordered by code
+func RegisteredGames() []Game {
+	gamesMu.RLock()
+	defer gamesMu.RUnlock()
+
+	list := make([]Game, 0, len(games))
+	for _, g := range games {
+		list = append(list, g)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code() < list[j].Code() })
+	return list
+}
+
+// StoredResult rebuilds the result saved on a drawn round
+func StoredResult(round *model.PC28Round) DrawResult {
+	numbers := NewGameService().JSONToKenoData(round.KenoData)
+	sort.Ints(numbers)
+	return DrawResult{
+		Numbers: numbers,
+		A:       round.ResultA,
+		B:       round.ResultB,
+		C:       round.ResultC,
+		Sum:     round.Sum,
+	}
+}
+
+// PC28Game is the PC28 implementation of Game on top of GameService
+type PC28Game struct {
+	svc *GameService
+}
+
+func NewPC28Game() *PC28Game {
+	return &PC28Game{svc: NewGameService()}
+}
+
+func (g *PC28Game) Code() string { return model.GameCodePC28 }
+
+func (g *PC28Game) Name() string { return "PC28" }
+
+func (g *PC28Game) Result(kenoData []int) DrawResult {
+	r := g.svc.CalculateResult(kenoData)
+	numbers := make([]int, len(kenoData))
+	copy(numbers, kenoData)
+	sort.Ints(numbers)
+	return DrawResult{Numbers: numbers, A: r.A, B: r.B, C: r.C, Sum: r.Sum}
+}
+
+func (g *PC28Game) ValidateBet(betType string, betValue int) error {
+	return g.svc.ValidateBet(betType, betValue)
+}
+
+func (g *PC28Game) CheckWin(betType string, betValue int, result DrawResult) bool {
+	return g.svc.CheckWin(betType, betValue, PC28Result{A: result.A, B: result.B, C: result.C, Sum: result.Sum})
+}
+
+func (g *PC28Game) DefaultSettings() *GameSettings {
+	return g.svc.DefaultGameSettings()
+}
+
+func (g *PC28Game) BetOdds(settings *GameSettings, betType string, betValue int) float64 {
+	return settings.BetOdds(betType, betValue)
+}
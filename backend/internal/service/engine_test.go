@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetGame(t *testing.T) {
+	for _, code := range []string{"", "pc28"} {
+		game, err := GetGame(code)
+		if err != nil {
+			t.Fatalf("GetGame(%q) error: %v", code, err)
+		}
+		if game.Code() != "pc28" {
+			t.Errorf("GetGame(%q) = %s, want pc28", code, game.Code())
+		}
+	}
+
+	if _, err := GetGame("lucky5"); !errors.Is(err, ErrUnknownGame) {
+		t.Errorf("GetGame(lucky5) error = %v, want ErrUnknownGame", err)
+	}
+
+	games := RegisteredGames()
+	if len(games) == 0 || games[0].Code() != "pc28" {
+		t.Errorf("RegisteredGames() does not include pc28")
+	}
+}
+
+func TestRegisterGameDuplicatePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("registering pc28 twice did not panic")
+		}
+	}()
+	RegisterGame(NewPC28Game())
+}
+
+func TestPC28GameMatchesGameService(t *testing.T) {
+	gs := NewGameService()
+	game := NewPC28Game()
+
+	for i := 0; i < 200; i++ {
+		kenoData := gs.GenerateMockKenoData()
+		want := gs.CalculateResult(kenoData)
+		got := game.Result(kenoData)
+
+		if got.A != want.A || got.B != want.B || got.C != want.C || got.Sum != want.Sum {
+			t.Fatalf("Result(%v) = %+v, want %+v", kenoData, got, want)
+		}
+		if len(got.Numbers) != 20 {
+			t.Fatalf("Result numbers len = %d, want 20", len(got.Numbers))
+		}
+		for j := 1; j < len(got.Numbers); j++ {
+			if got.Numbers[j-1] > got.Numbers[j] {
+				t.Fatalf("Result numbers not sorted: %v", got.Numbers)
+			}
+		}
+
+		for betType := range gs.GetOdds() {
+			if game.CheckWin(betType, 3, got) != gs.CheckWin(betType, 3, want) {
+				t.Fatalf("CheckWin(%s) differs for %+v", betType, want)
+			}
+		}
+	}
+
+	settings := game.DefaultSettings()
+	if game.BetOdds(settings, "number", 13) != settings.NumberOdds[13] {
+		t.Errorf("BetOdds(number, 13) does not use the number odds table")
+	}
+	if err := game.ValidateBet("number", 28); err == nil {
+		t.Errorf("ValidateBet(number, 28) should fail")
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// Provably fair draws (commit–reveal)
+//
+// When a round opens the server picks a random seed and publishes only
+// SHA256(seed). After the round closes the seed is revealed and the 20 Keno
+// numbers are derived from HMAC-SHA256(seed, salt) where the public salt is the
+// round's issue number. Anyone can recompute the hash and the draw.
+
+// GenerateServerSeed returns a new random 32-byte seed, hex encoded
+func (s *GameService) GenerateServerSeed() string {
+	bytes := make([]byte, 32)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+// HashServerSeed returns the hex SHA256 commitment of a seed
+func (s *GameService) HashServerSeed(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateSeededKenoData derives 20 unique numbers between 1-80 from a seed and salt
+// Algorithm: Fisher-Yates shuffle of 1..80, each swap index drawn from the
+// HMAC-SHA256(seed, "salt:counter") byte stream with rejection sampling
+func (s *GameService) GenerateSeededKenoData(seed, salt string) []int {
+	numbers := make([]int, 80)
+	for i := 0; i < 80; i++ {
+		numbers[i] = i + 1
+	}
+
+	stream := &seedStream{seed: []byte(seed), salt: salt}
+	for i := len(numbers) - 1; i > 0; i-- {
+		j := stream.intn(i + 1)
+		numbers[i], numbers[j] = numbers[j], numbers[i]
+	}
+
+	return numbers[len(numbers)-20:]
+}
+
+// seedStream yields deterministic uint32 values from HMAC-SHA256 blocks
+type seedStream struct {
+	seed    []byte
+	salt    string
+	counter int
+	buf     []byte
+}
+
+func (st *seedStream) next() uint32 {
+	if len(st.buf) < 4 {
+		mac := hmac.New(sha256.New, st.seed)
+		mac.Write([]byte(fmt.Sprintf("%s:%d", st.salt, st.counter)))
+		st.buf = mac.Sum(nil)
+		st.counter++
+	}
+	v := binary.BigEndian.Uint32(st.buf[:4])
+	st.buf = st.buf[4:]
+	return v
+}
+
+// intn returns an unbiased value in [0, n)
+func (st *seedStream) intn(n int) int {
+	limit := ^uint32(0) - ^uint32(0)%uint32(n)
+	for {
+		v := st.next()
+		if v < limit {
+			return int(v % uint32(n))
+		}
+	}
+}
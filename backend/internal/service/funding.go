@@ -0,0 +1,161 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"pcgame/backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrFundingNotFound   = errors.New("funding request not found")
+	ErrFundingNotPending = errors.New("funding request has already been reviewed")
+	ErrInvalidFunding    = errors.New("invalid funding request")
+)
+
+// FundingSubmission is a new deposit or withdrawal request from a player
+type FundingSubmission struct {
+	Type    model.FundingType
+	Amount  model.Money
+	Method  string
+	Account string
+	Note    string
+}
+
+// FundingResult is a funding request after a change, with the player's balance
+type FundingResult struct {
+	Request        model.FundingRequest
+	Balance        model.Money
+	BalanceChanged bool
+}
+
+// FundingService handles deposit and withdrawal requests (充值/提现)
+type FundingService struct {
+	db *gorm.DB
+}
+
+func NewFundingService(db *gorm.DB) *FundingService {
+	return &FundingService{db: db}
+}
+
+// Submit records a player's request. A withdrawal holds its amount from the
+// balance in the same transaction and fails if the balance cannot cover it;
+// a deposit only leaves a zero-amount ledger entry until it is approved.
+func (s *FundingService) Submit(userID uint, sub FundingSubmission) (*FundingResult, error) {
+	if sub.Type != model.FundingDeposit && sub.Type != model.FundingWithdrawal {
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidFunding, sub.Type)
+	}
+	if sub.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidFunding)
+	}
+
+	res := FundingResult{Request: model.FundingRequest{
+		UserID:  userID,
+		Type:    sub.Type,
+		Amount:  sub.Amount,
+		Status:  model.FundingStatusPending,
+		Method:  sub.Method,
+		Account: sub.Account,
+		Note:    sub.Note,
+	}}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&res.Request).Error; err != nil {
+			return err
+		}
+		if sub.Type == model.FundingWithdrawal {
+			if err := Debit(tx, userID, sub.Amount, fundingEntry(model.WalletTxWithdrawal, &res.Request, "")); err != nil {
+				return err
+			}
+			res.BalanceChanged = true
+		} else if err := Record(tx, userID, fundingEntry(model.WalletTxDepositRequest, &res.Request, "")); err != nil {
+			return err
+		}
+		return readBalance(tx, userID, &res.Balance)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Approve accepts a pending request: a deposit is credited, a withdrawal's
+// held amount is considered paid out
+func (s *FundingService) Approve(id, adminID uint, note string) (*FundingResult, error) {
+	return s.review(id, adminID, model.FundingStatusApproved, note)
+}
+
+// Reject declines a pending request: a withdrawal's held amount is returned
+func (s *FundingService) Reject(id, adminID uint, note string) (*FundingResult, error) {
+	return s.review(id, adminID, model.FundingStatusRejected, note)
+}
+
+// review moves a locked pending request to its final status, so each
+// request is credited or released at most once. Every outcome is posted to
+// the ledger, with a zero amount when no money moves.
+func (s *FundingService) review(id, adminID uint, status model.FundingStatus, note string) (*FundingResult, error) {
+	var res FundingResult
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		req := &res.Request
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(req, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrFundingNotFound
+			}
+			return err
+		}
+		if req.Status != model.FundingStatusPending {
+			return ErrFundingNotPending
+		}
+
+		var err error
+		switch {
+		case req.Type == model.FundingDeposit && status == model.FundingStatusApproved:
+			err = Credit(tx, req.UserID, req.Amount, fundingEntry(model.WalletTxDeposit, req, note))
+			res.BalanceChanged = true
+		case req.Type == model.FundingDeposit:
+			err = Record(tx, req.UserID, fundingEntry(model.WalletTxDepositRejected, req, note))
+		case status == model.FundingStatusApproved:
+			err = Record(tx, req.UserID, fundingEntry(model.WalletTxWithdrawalPaid, req, note))
+		default:
+			err = Credit(tx, req.UserID, req.Amount, fundingEntry(model.WalletTxWithdrawalRefund, req, note))
+			res.BalanceChanged = true
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		req.Status = status
+		req.ReviewedByID = &adminID
+		req.ReviewedAt = &now
+		req.ReviewNote = note
+		if err := tx.Model(req).Updates(map[string]interface{}{
+			"status":         status,
+			"reviewed_by_id": adminID,
+			"reviewed_at":    now,
+			"review_note":    note,
+		}).Error; err != nil {
+			return err
+		}
+		return readBalance(tx, req.UserID, &res.Balance)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// readBalance loads a player's current balance inside a transaction
+func readBalance(tx *gorm.DB, userID uint, balance *model.Money) error {
+	return tx.Model(&model.User{}).Where("id = ?", userID).Select("balance").Scan(balance).Error
+}
+
+// fundingEntry is the ledger entry of a change caused by a funding request
+func fundingEntry(txType model.WalletTxType, req *model.FundingRequest, note string) LedgerEntry {
+	return LedgerEntry{Type: txType, RefType: "funding", RefID: &req.ID, Note: note}
+}
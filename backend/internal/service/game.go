@@ -2,6 +2,7 @@ package service
 
 import (
 	"encoding/json"
+	"fmt"
 	"math/rand"
 	"sort"
 	"time"
@@ -99,10 +100,64 @@ func (s *GameService) JSONToKenoData(jsonStr string) []int {
 	return data
 }
 
+// numberOdds holds the payout odds of a "number" bet for each sum 0-27.
+// Sums near 13/14 are far more likely than 0 or 27; the table targets ~97% RTP
+// under the uniform-digit approximation (see the sum counts in comments).
+var numberOdds = [28]float64{
+	970.0, // 0  (1/1000)
+	323.3, // 1  (3/1000)
+	161.7, // 2  (6/1000)
+	97.0,  // 3  (10/1000)
+	64.7,  // 4  (15/1000)
+	46.2,  // 5  (21/1000)
+	34.6,  // 6  (28/1000)
+	26.9,  // 7  (36/1000)
+	21.6,  // 8  (45/1000)
+	17.6,  // 9  (55/1000)
+	15.4,  // 10 (63/1000)
+	14.1,  // 11 (69/1000)
+	13.3,  // 12 (73/1000)
+	12.9,  // 13 (75/1000)
+	12.9,  // 14 (75/1000)
+	13.3,  // 15 (73/1000)
+	14.1,  // 16 (69/1000)
+	15.4,  // 17 (63/1000)
+	17.6,  // 18 (55/1000)
+	21.6,  // 19 (45/1000)
+	26.9,  // 20 (36/1000)
+	34.6,  // 21 (28/1000)
+	46.2,  // 22 (21/1000)
+	64.7,  // 23 (15/1000)
+	97.0,  // 24 (10/1000)
+	161.7, // 25 (6/1000)
+	323.3, // 26 (3/1000)
+	970.0, // 27 (1/1000)
+}
+
+// GetNumberOdds returns the odds table for "number" bets, keyed by sum
+func (s *GameService) GetNumberOdds() map[int]float64 {
+	table := make(map[int]float64, len(numberOdds))
+	for sum, odds := range numberOdds {
+		table[sum] = odds
+	}
+	return table
+}
+
+// GetBetOdds returns the odds for a specific bet, or 0 if the bet is invalid
+func (s *GameService) GetBetOdds(betType string, betValue int) float64 {
+	if betType == "number" {
+		if betValue < 0 || betValue >= len(numberOdds) {
+			return 0
+		}
+		return numberOdds[betValue]
+	}
+	return s.GetOdds()[betType]
+}
+
 // GetOdds returns the payout odds for each bet type
+// "number" bets are priced per sum, see GetNumberOdds
 func (s *GameService) GetOdds() map[string]float64 {
 	return map[string]float64{
-		"number":     9.8,  // Specific number (0-27), varies by number
 		"big":        1.95, // 14-27
 		"small":      1.95, // 0-13
 		"odd":        1.95, // Odd sum
@@ -111,7 +166,35 @@ func (s *GameService) GetOdds() map[string]float64 {
 		"big_even":   3.7,  // Big + Even
 		"small_odd":  3.7,  // Small + Odd
 		"small_even": 3.7,  // Small + Even
+
+		"extreme_big":   15,  // 22-27
+		"extreme_small": 15,  // 0-5
+		"leopard":       80,  // A = B = C
+		"pair":          3.2, // Exactly two digits equal
+		"straight":      14,  // Consecutive digits
+		"digit_a":       9.5, // A equals bet value
+		"digit_b":       9.5, // B equals bet value
+		"digit_c":       9.5, // C equals bet value
+	}
+}
+
+// ValidateBet checks that the bet value is in range for the bet type
+func (s *GameService) ValidateBet(betType string, betValue int) error {
+	switch betType {
+	case "number":
+		if betValue < 0 || betValue > 27 {
+			return fmt.Errorf("bet value for %s must be 0-27", betType)
+		}
+	case "digit_a", "digit_b", "digit_c":
+		if betValue < 0 || betValue > 9 {
+			return fmt.Errorf("bet value for %s must be 0-9", betType)
+		}
+	default:
+		if _, ok := s.GetOdds()[betType]; !ok {
+			return fmt.Errorf("invalid bet type %s", betType)
+		}
 	}
+	return nil
 }
 
 // CheckWin determines if a bet wins based on the result
@@ -137,7 +220,44 @@ func (s *GameService) CheckWin(betType string, betValue int, result PC28Result)
 		return sum <= 13 && sum%2 == 1
 	case "small_even":
 		return sum <= 13 && sum%2 == 0
+	case "extreme_big":
+		return sum >= 22
+	case "extreme_small":
+		return sum <= 5
+	case "leopard":
+		return result.A == result.B && result.B == result.C
+	case "pair":
+		return isPair(result)
+	case "straight":
+		return isStraight(result)
+	case "digit_a":
+		return betValue == result.A
+	case "digit_b":
+		return betValue == result.B
+	case "digit_c":
+		return betValue == result.C
 	default:
 		return false
 	}
 }
+
+// isPair reports whether exactly two of the three digits are equal (leopard excluded)
+func isPair(r PC28Result) bool {
+	if r.A == r.B && r.B == r.C {
+		return false
+	}
+	return r.A == r.B || r.B == r.C || r.A == r.C
+}
+
+// isStraight reports whether the digits are three consecutive values in any
+// order; 8-9-0 and 9-0-1 wrap around and also count
+func isStraight(r PC28Result) bool {
+	digits := []int{r.A, r.B, r.C}
+	sort.Ints(digits)
+	if digits[1] == digits[0]+1 && digits[2] == digits[1]+1 {
+		return true
+	}
+	// Wrap-around: {0, 8, 9} and {0, 1, 9}
+	return (digits[0] == 0 && digits[1] == 8 && digits[2] == 9) ||
+		(digits[0] == 0 && digits[1] == 1 && digits[2] == 9)
+}
@@ -93,6 +93,22 @@ func TestCheckWin(t *testing.T) {
 		{"big_odd win", "big_odd", 0, PC28Result{Sum: 17}, true},
 		{"big_odd lose - small", "big_odd", 0, PC28Result{Sum: 7}, false},
 		{"big_odd lose - even", "big_odd", 0, PC28Result{Sum: 16}, false},
+		{"extreme_big win", "extreme_big", 0, PC28Result{Sum: 22}, true},
+		{"extreme_big lose", "extreme_big", 0, PC28Result{Sum: 21}, false},
+		{"extreme_small win", "extreme_small", 0, PC28Result{Sum: 5}, true},
+		{"extreme_small lose", "extreme_small", 0, PC28Result{Sum: 6}, false},
+		{"leopard win", "leopard", 0, PC28Result{A: 7, B: 7, C: 7, Sum: 21}, true},
+		{"leopard lose", "leopard", 0, PC28Result{A: 7, B: 7, C: 6, Sum: 20}, false},
+		{"pair win", "pair", 0, PC28Result{A: 3, B: 5, C: 3, Sum: 11}, true},
+		{"pair lose - leopard", "pair", 0, PC28Result{A: 3, B: 3, C: 3, Sum: 9}, false},
+		{"pair lose - distinct", "pair", 0, PC28Result{A: 1, B: 2, C: 4, Sum: 7}, false},
+		{"straight win", "straight", 0, PC28Result{A: 5, B: 3, C: 4, Sum: 12}, true},
+		{"straight win - wrap 890", "straight", 0, PC28Result{A: 9, B: 0, C: 8, Sum: 17}, true},
+		{"straight win - wrap 901", "straight", 0, PC28Result{A: 1, B: 9, C: 0, Sum: 10}, true},
+		{"straight lose", "straight", 0, PC28Result{A: 1, B: 3, C: 4, Sum: 8}, false},
+		{"digit_a win", "digit_a", 4, PC28Result{A: 4, B: 1, C: 2, Sum: 7}, true},
+		{"digit_b lose", "digit_b", 4, PC28Result{A: 4, B: 1, C: 2, Sum: 7}, false},
+		{"digit_c win", "digit_c", 2, PC28Result{A: 4, B: 1, C: 2, Sum: 7}, true},
 	}
 
 	for _, tt := range tests {
@@ -125,3 +141,85 @@ func TestGenerateMockKenoData(t *testing.T) {
 		seen[n] = true
 	}
 }
+
+func TestGenerateSeededKenoData(t *testing.T) {
+	gs := NewGameService()
+	seed := gs.GenerateServerSeed()
+
+	first := gs.GenerateSeededKenoData(seed, "20240101120000")
+	second := gs.GenerateSeededKenoData(seed, "20240101120000")
+	if err := ValidateKenoData(first); err != nil {
+		t.Fatalf("invalid seeded draw: %v", err)
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("seeded draw not deterministic: %v vs %v", first, second)
+		}
+	}
+
+	other := gs.GenerateSeededKenoData(seed, "20240101120100")
+	same := true
+	for i := range first {
+		if first[i] != other[i] {
+			same = false
+		}
+	}
+	if same {
+		t.Error("different salts produced the same draw")
+	}
+
+	if gs.HashServerSeed(seed) != gs.HashServerSeed(seed) || len(gs.HashServerSeed(seed)) != 64 {
+		t.Error("HashServerSeed should be a stable 64-char hex digest")
+	}
+}
+
+func TestValidateBet(t *testing.T) {
+	gs := NewGameService()
+
+	tests := []struct {
+		betType  string
+		betValue int
+		wantErr  bool
+	}{
+		{"number", 27, false},
+		{"number", 28, true},
+		{"digit_a", 9, false},
+		{"digit_b", 10, true},
+		{"leopard", 0, false},
+		{"unknown", 0, true},
+	}
+
+	for _, tt := range tests {
+		err := gs.ValidateBet(tt.betType, tt.betValue)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateBet(%s, %d) error = %v, wantErr %v", tt.betType, tt.betValue, err, tt.wantErr)
+		}
+	}
+}
+
+func TestGetBetOdds(t *testing.T) {
+	gs := NewGameService()
+
+	table := gs.GetNumberOdds()
+	if len(table) != 28 {
+		t.Fatalf("number odds table has %d entries, want 28", len(table))
+	}
+	for sum := 0; sum <= 13; sum++ {
+		if table[sum] != table[27-sum] {
+			t.Errorf("odds for %d (%v) and %d (%v) should be symmetric", sum, table[sum], 27-sum, table[27-sum])
+		}
+	}
+	if table[0] <= table[13] {
+		t.Errorf("odds for 0 (%v) should exceed odds for 13 (%v)", table[0], table[13])
+	}
+
+	if got := gs.GetBetOdds("number", 14); got != table[14] {
+		t.Errorf("GetBetOdds(number, 14) = %v, want %v", got, table[14])
+	}
+	if got := gs.GetBetOdds("number", 28); got != 0 {
+		t.Errorf("GetBetOdds(number, 28) = %v, want 0", got)
+	}
+	if got := gs.GetBetOdds("big", 0); got != gs.GetOdds()["big"] {
+		t.Errorf("GetBetOdds(big) = %v, want %v", got, gs.GetOdds()["big"])
+	}
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"pcgame/backend/internal/config"
+)
+
+// ErrDrawNotReady is returned when the provider has not published the draw yet
+var ErrDrawNotReady = errors.New("keno draw not ready")
+
+// KenoSource provides the 20 Keno numbers for a given issue
+type KenoSource interface {
+	// FetchDraw returns the draw for the issue, or ErrDrawNotReady
+	FetchDraw(ctx context.Context, issueNumber string) ([]int, error)
+	// Name identifies the source in logs
+	Name() string
+}
+
+// SeededKenoSource is implemented by sources that can derive a draw from a
+// committed server seed, enabling provably fair rounds
+type SeededKenoSource interface {
+	KenoSource
+	DrawFromSeed(seed, salt string) []int
+}
+
+// IssueMappedKenoSource is implemented by sources that number draws
+// themselves; a round is drawn with the provider issue following its close
+type IssueMappedKenoSource interface {
+	KenoSource
+	ProviderIssue(closeTime time.Time) string
+}
+
+// NewKenoSource creates the Keno source selected by configuration
+func NewKenoSource(cfg *config.Config, gameSvc *GameService) KenoSource {
+	if cfg.Game.UseMockData || cfg.Keno.APIURL == "" {
+		return NewMockKenoSource(gameSvc)
+	}
+	schedule := DrawSchedule{
+		AnchorIssue: cfg.Keno.AnchorIssue,
+		AnchorTime:  cfg.Keno.AnchorTime,
+		Interval:    time.Duration(cfg.Keno.DrawInterval) * time.Second,
+	}
+	return NewHTTPKenoSource(cfg.Keno.APIURL, cfg.Keno.APIKey, time.Duration(cfg.Keno.Timeout)*time.Second, schedule)
+}
+
+// DrawSchedule maps times to a provider's issue numbers, which go up by one
+// every Interval from AnchorIssue drawn at AnchorTime
+type DrawSchedule struct {
+	AnchorIssue int64
+	AnchorTime  time.Time
+	Interval    time.Duration
+}
+
+// IssueAfter returns the provider issue of the first draw at or after t
+func (d DrawSchedule) IssueAfter(t time.Time) string {
+	n := t.Sub(d.AnchorTime) / d.Interval
+	if d.AnchorTime.Add(n * d.Interval).Before(t) {
+		n++
+	}
+	return strconv.FormatInt(d.AnchorIssue+int64(n), 10)
+}
+
+// ==========================================
+// Mock Source
+// ==========================================
+
+// MockKenoSource generates random draws locally
+type MockKenoSource struct {
+	gameSvc *GameService
+}
+
+// NewMockKenoSource creates a new mock source
+func NewMockKenoSource(gameSvc *GameService) *MockKenoSource {
+	return &MockKenoSource{gameSvc: gameSvc}
+}
+
+// FetchDraw always returns a fresh random draw
+func (m *MockKenoSource) FetchDraw(ctx context.Context, issueNumber string) ([]int, error) {
+	return m.gameSvc.GenerateMockKenoData(), nil
+}
+
+// Name returns the source name
+func (m *MockKenoSource) Name() string {
+	return "mock"
+}
+
+// DrawFromSeed derives the draw from a revealed server seed
+func (m *MockKenoSource) DrawFromSeed(seed, salt string) []int {
+	return m.gameSvc.GenerateSeededKenoData(seed, salt)
+}
+
+// ==========================================
+// HTTP Source
+// ==========================================
+
+// KenoDrawResponse is the JSON body returned by a Keno provider
+// GET {api_url}?issue=<provider issue>
+type KenoDrawResponse struct {
+	Issue   string `json:"issue"`
+	Numbers []int  `json:"numbers"`
+}
+
+// HTTPKenoSource fetches draws from a remote Keno API
+type HTTPKenoSource struct {
+	apiURL   string
+	apiKey   string
+	schedule DrawSchedule
+	client   *http.Client
+}
+
+// NewHTTPKenoSource creates a new HTTP source for a provider drawing on schedule
+func NewHTTPKenoSource(apiURL, apiKey string, timeout time.Duration, schedule DrawSchedule) *HTTPKenoSource {
+	if timeout <= 0 {
+		timeout = 3 * time.Second
+	}
+	return &HTTPKenoSource{
+		apiURL:   apiURL,
+		apiKey:   apiKey,
+		schedule: schedule,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// ProviderIssue returns the provider's first draw at or after a round closes
+func (h *HTTPKenoSource) ProviderIssue(closeTime time.Time) string {
+	return h.schedule.IssueAfter(closeTime)
+}
+
+// FetchDraw requests the draw for a provider issue
+func (h *HTTPKenoSource) FetchDraw(ctx context.Context, issueNumber string) ([]int, error) {
+	u, err := url.Parse(h.apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keno api url: %w", err)
+	}
+	q := u.Query()
+	q.Set("issue", issueNumber)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if h.apiKey != "" {
+		req.Header.Set("X-API-Key", h.apiKey)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// 404 / 202 means the provider knows nothing about this issue yet
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusAccepted {
+		return nil, ErrDrawNotReady
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("keno api returned status %d", resp.StatusCode)
+	}
+
+	var body KenoDrawResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid keno api response: %w", err)
+	}
+	if len(body.Numbers) == 0 {
+		return nil, ErrDrawNotReady
+	}
+	if body.Issue != "" && body.Issue != issueNumber {
+		return nil, fmt.Errorf("keno api returned issue %s, want %s", body.Issue, issueNumber)
+	}
+	if err := ValidateKenoData(body.Numbers); err != nil {
+		return nil, err
+	}
+
+	return body.Numbers, nil
+}
+
+// Name returns the source name
+func (h *HTTPKenoSource) Name() string {
+	return "http"
+}
+
+// ValidateKenoData checks that a draw holds 20 unique numbers between 1-80
+func ValidateKenoData(data []int) error {
+	if len(data) != 20 {
+		return fmt.Errorf("keno draw must have 20 numbers, got %d", len(data))
+	}
+	seen := make(map[int]bool, len(data))
+	for _, n := range data {
+		if n < 1 || n > 80 {
+			return fmt.Errorf("keno number %d out of range 1-80", n)
+		}
+		if seen[n] {
+			return fmt.Errorf("duplicate keno number %d", n)
+		}
+		seen[n] = true
+	}
+	return nil
+}
+
+// FetchDrawWithRetry polls the source until the draw is ready or retries run out
+func FetchDrawWithRetry(ctx context.Context, src KenoSource, issueNumber string, maxRetries int, interval time.Duration) ([]int, error) {
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+
+		data, err := src.FetchDraw(ctx, issueNumber)
+		if err == nil {
+			return data, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
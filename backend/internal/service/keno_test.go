@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPKenoSource(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Query().Get("issue") {
+		case "ready":
+			json.NewEncoder(w).Encode(KenoDrawResponse{Issue: "ready", Numbers: numbers})
+		case "short":
+			json.NewEncoder(w).Encode(KenoDrawResponse{Issue: "short", Numbers: numbers[:10]})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	src := NewHTTPKenoSource(server.URL, "secret", time.Second, DrawSchedule{})
+
+	data, err := src.FetchDraw(context.Background(), "ready")
+	if err != nil {
+		t.Fatalf("FetchDraw(ready) error = %v", err)
+	}
+	if len(data) != 20 {
+		t.Errorf("Expected 20 numbers, got %d", len(data))
+	}
+
+	if _, err := src.FetchDraw(context.Background(), "later"); !errors.Is(err, ErrDrawNotReady) {
+		t.Errorf("FetchDraw(later) error = %v, want ErrDrawNotReady", err)
+	}
+
+	if _, err := src.FetchDraw(context.Background(), "short"); err == nil {
+		t.Error("FetchDraw(short) expected validation error")
+	}
+
+	bad := NewHTTPKenoSource(server.URL, "wrong", time.Second, DrawSchedule{})
+	if _, err := bad.FetchDraw(context.Background(), "ready"); err == nil {
+		t.Error("FetchDraw with wrong key expected error")
+	}
+}
+
+type flakySource struct {
+	failures int
+	calls    int
+}
+
+func (f *flakySource) FetchDraw(ctx context.Context, issueNumber string) ([]int, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, ErrDrawNotReady
+	}
+	return NewGameService().GenerateMockKenoData(), nil
+}
+
+func (f *flakySource) Name() string { return "flaky" }
+
+func TestFetchDrawWithRetry(t *testing.T) {
+	src := &flakySource{failures: 2}
+	data, err := FetchDrawWithRetry(context.Background(), src, "1", 3, time.Millisecond)
+	if err != nil {
+		t.Fatalf("FetchDrawWithRetry() error = %v", err)
+	}
+	if len(data) != 20 || src.calls != 3 {
+		t.Errorf("got %d numbers after %d calls, want 20 after 3", len(data), src.calls)
+	}
+
+	src = &flakySource{failures: 5}
+	if _, err := FetchDrawWithRetry(context.Background(), src, "1", 2, time.Millisecond); !errors.Is(err, ErrDrawNotReady) {
+		t.Errorf("FetchDrawWithRetry() error = %v, want ErrDrawNotReady", err)
+	}
+	if src.calls != 3 {
+		t.Errorf("calls = %d, want 3", src.calls)
+	}
+}
+
+func TestDrawScheduleIssueAfter(t *testing.T) {
+	anchor := time.Date(2026, 1, 22, 9, 0, 0, 0, time.UTC)
+	d := DrawSchedule{AnchorIssue: 3350000, AnchorTime: anchor, Interval: 5 * time.Minute}
+
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{anchor, "3350000"},
+		{anchor.Add(time.Second), "3350001"},
+		{anchor.Add(5 * time.Minute), "3350001"},
+		{anchor.Add(12 * time.Minute), "3350003"},
+		{anchor.Add(-time.Second), "3350000"},
+		{anchor.Add(-5 * time.Minute), "3349999"},
+		{anchor.Add(-7 * time.Minute), "3349999"},
+	}
+
+	for _, tt := range tests {
+		if got := d.IssueAfter(tt.at); got != tt.want {
+			t.Errorf("IssueAfter(%s) = %s, want %s", tt.at.Format(time.TimeOnly), got, tt.want)
+		}
+	}
+
+	src := NewHTTPKenoSource("http://keno.invalid", "", time.Second, d)
+	if got := src.ProviderIssue(anchor.Add(time.Minute)); got != "3350001" {
+		t.Errorf("ProviderIssue() = %s, want 3350001", got)
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"pcgame/backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIllegalTransition = errors.New("illegal round transition")
+	ErrRoundConflict     = errors.New("round was modified concurrently")
+)
+
+// roundTransitions lists the legal next states of each round state:
+// pending -> open -> closed -> settled, and any unfinished state -> void
+var roundTransitions = map[model.RoundStatus][]model.RoundStatus{
+	model.RoundStatusPending: {model.RoundStatusOpen, model.RoundStatusVoid},
+	model.RoundStatusOpen:    {model.RoundStatusClosed, model.RoundStatusVoid},
+	model.RoundStatusClosed:  {model.RoundStatusSettled, model.RoundStatusVoid},
+}
+
+// CanTransition reports whether a round may move from one state to another
+func CanTransition(from, to model.RoundStatus) bool {
+	for _, next := range roundTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// RoundEvent is emitted after a round changes state
+type RoundEvent struct {
+	Round model.PC28Round   `json:"round"` // Round after the transition
+	From  model.RoundStatus `json:"from"`
+	To    model.RoundStatus `json:"to"`
+	At    time.Time         `json:"at"`
+}
+
+// RoundEventHandler consumes round events; it runs on the publishing goroutine
+// and should not block
+type RoundEventHandler func(RoundEvent)
+
+// roundSubscription is a registered handler; the id lets it be removed
+type roundSubscription struct {
+	id      int
+	handler RoundEventHandler
+}
+
+var (
+	roundEventsMu sync.RWMutex
+	roundHandlers []roundSubscription
+	nextHandlerID int
+)
+
+// SubscribeRoundEvents registers a handler for every round transition in this
+// process. The returned function removes it; calling it again does nothing.
+func SubscribeRoundEvents(h RoundEventHandler) (unsubscribe func()) {
+	roundEventsMu.Lock()
+	defer roundEventsMu.Unlock()
+	nextHandlerID++
+	id := nextHandlerID
+	roundHandlers = append(roundHandlers, roundSubscription{id: id, handler: h})
+
+	return func() {
+		roundEventsMu.Lock()
+		defer roundEventsMu.Unlock()
+		for i, sub := range roundHandlers {
+			if sub.id == id {
+				roundHandlers = append(roundHandlers[:i:i], roundHandlers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// PublishRoundEvent delivers an event to all subscribers
+// Call it only after the transition has been committed
+func PublishRoundEvent(ev *RoundEvent) {
+	if ev == nil {
+		return
+	}
+
+	roundEventsMu.RLock()
+	handlers := make([]roundSubscription, len(roundHandlers))
+	copy(handlers, roundHandlers)
+	roundEventsMu.RUnlock()
+
+	for _, sub := range handlers {
+		sub.handler(*ev)
+	}
+}
+
+// RoundLifecycle is the only place round states change. Each transition is
+// checked against the state machine and applied as an optimistic-locked
+// update on (status, version), so a stale copy of a round can never
+// overwrite a newer state.
+type RoundLifecycle struct {
+	db *gorm.DB
+}
+
+func NewRoundLifecycle(db *gorm.DB) *RoundLifecycle {
+	return &RoundLifecycle{db: db}
+}
+
+// Transition moves a round to a new state, updating extra columns in the same
+// statement, and publishes the event
+func (l *RoundLifecycle) Transition(round *model.PC28Round, to model.RoundStatus, fields map[string]interface{}) error {
+	ev, err := l.TransitionTx(l.db, round, to, fields)
+	if err != nil {
+		return err
+	}
+	PublishRoundEvent(ev)
+	return nil
+}
+
+// TransitionTx applies a transition inside the caller's transaction and returns
+// the event, which the caller publishes once the transaction commits
+func (l *RoundLifecycle) TransitionTx(tx *gorm.DB, round *model.PC28Round, to model.RoundStatus, fields map[string]interface{}) (*RoundEvent, error) {
+	from := round.Status
+	if !CanTransition(from, to) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrIllegalTransition, from, to)
+	}
+
+	updates := map[string]interface{}{
+		"status":  to,
+		"version": gorm.Expr("version + 1"),
+	}
+	for k, v := range fields {
+		updates[k] = v
+	}
+
+	res := tx.Model(&model.PC28Round{}).
+		Where("id = ? AND status = ? AND version = ?", round.ID, from, round.Version).
+		Updates(updates)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: round %s is no longer %s", ErrRoundConflict, round.IssueNumber, from)
+	}
+
+	round.Status = to
+	round.Version++
+	return &RoundEvent{Round: *round, From: from, To: to, At: time.Now()}, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"testing"
+
+	"pcgame/backend/internal/model"
+)
+
+func TestCanTransition(t *testing.T) {
+	const (
+		pending = model.RoundStatusPending
+		open    = model.RoundStatusOpen
+		closed  = model.RoundStatusClosed
+		settled = model.RoundStatusSettled
+		void    = model.RoundStatusVoid
+	)
+
+	tests := []struct {
+		from, to model.RoundStatus
+		want     bool
+	}{
+		{pending, open, true},
+		{open, closed, true},
+		{closed, settled, true},
+		{pending, void, true},
+		{open, void, true},
+		{closed, void, true},
+
+		{pending, closed, false},
+		{pending, settled, false},
+		{open, settled, false},
+		{open, pending, false},
+		{closed, open, false},
+		{settled, closed, false},
+		{settled, void, false},
+		{void, open, false},
+		{void, settled, false},
+		{open, open, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestTransitionRejectsIllegalWithoutDB(t *testing.T) {
+	// Illegal transitions are refused before any query is made
+	l := NewRoundLifecycle(nil)
+	round := &model.PC28Round{Status: model.RoundStatusSettled}
+
+	if _, err := l.TransitionTx(nil, round, model.RoundStatusClosed, nil); err == nil {
+		t.Fatal("settled -> closed should be refused")
+	}
+	if round.Status != model.RoundStatusSettled || round.Version != 0 {
+		t.Errorf("refused transition modified the round: %+v", round)
+	}
+}
+
+func TestPublishRoundEvent(t *testing.T) {
+	var got, other []RoundEvent
+	unsubscribe := SubscribeRoundEvents(func(ev RoundEvent) { got = append(got, ev) })
+	defer SubscribeRoundEvents(func(ev RoundEvent) { other = append(other, ev) })()
+
+	PublishRoundEvent(nil)
+	PublishRoundEvent(&RoundEvent{From: model.RoundStatusOpen, To: model.RoundStatusClosed})
+
+	if len(got) != 1 || got[0].To != model.RoundStatusClosed {
+		t.Errorf("subscriber received %+v, want one open -> closed event", got)
+	}
+
+	unsubscribe()
+	unsubscribe()
+	PublishRoundEvent(&RoundEvent{From: model.RoundStatusClosed, To: model.RoundStatusSettled})
+
+	if len(got) != 1 {
+		t.Errorf("unsubscribed handler received %d events, want 1", len(got))
+	}
+	if len(other) != 2 {
+		t.Errorf("remaining handler received %d events, want 2", len(other))
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"sort"
+	"sync"
+)
+
+// Exact outcome distribution of CalculateResult
+//
+// A draw is 20 distinct numbers out of 1-80. After sorting, A/B/C are the
+// sums of sorted positions 0-5, 6-11 and 12-17 mod 10. Walking the numbers
+// 1..80 in ascending order, a number that is picked becomes the next sorted
+// position, so a dynamic program over (picked count, A, B, C) counts every
+// one of the C(80,20) draws exactly. All counts fit in a uint64.
+
+const (
+	kenoPool  = 80
+	kenoDrawn = 20
+)
+
+// OutcomeCounts holds the number of draws producing each (A, B, C)
+type OutcomeCounts struct {
+	Counts [10][10][10]uint64
+	Total  uint64
+}
+
+var (
+	outcomeOnce   sync.Once
+	outcomeCounts *OutcomeCounts
+)
+
+// ExactOutcomeCounts returns the exact (A, B, C) distribution for the real draw
+func (s *GameService) ExactOutcomeCounts() *OutcomeCounts {
+	outcomeOnce.Do(func() {
+		outcomeCounts = computeOutcomeCounts(kenoPool, kenoDrawn)
+	})
+	return outcomeCounts
+}
+
+// computeOutcomeCounts runs the DP for a pool of 1..pool with drawn numbers picked
+// Requires drawn >= 18 so that all three groups are filled
+func computeOutcomeCounts(pool, drawn int) *OutcomeCounts {
+	// dp[k][a][b][c]: ways to pick k numbers so far with the given group sums
+	type state [10][10][10]uint64
+	dp := make([]state, drawn+1)
+	dp[0][0][0][0] = 1
+
+	for v := 1; v <= pool; v++ {
+		// Iterate k downwards so each number is picked at most once
+		for k := min(drawn-1, v-1); k >= 0; k-- {
+			for a := 0; a < 10; a++ {
+				for b := 0; b < 10; b++ {
+					for c := 0; c < 10; c++ {
+						ways := dp[k][a][b][c]
+						if ways == 0 {
+							continue
+						}
+						na, nb, nc := a, b, c
+						switch {
+						case k < 6:
+							na = (a + v) % 10
+						case k < 12:
+							nb = (b + v) % 10
+						case k < 18:
+							nc = (c + v) % 10
+						}
+						dp[k+1][na][nb][nc] += ways
+					}
+				}
+			}
+		}
+	}
+
+	out := &OutcomeCounts{Counts: dp[drawn]}
+	for a := 0; a < 10; a++ {
+		for b := 0; b < 10; b++ {
+			for c := 0; c < 10; c++ {
+				out.Total += out.Counts[a][b][c]
+			}
+		}
+	}
+	return out
+}
+
+// Probability returns the probability of an exact (A, B, C) outcome
+func (oc *OutcomeCounts) Probability(a, b, c int) float64 {
+	return float64(oc.Counts[a][b][c]) / float64(oc.Total)
+}
+
+// SumProbabilities returns the probability of each sum 0-27
+func (oc *OutcomeCounts) SumProbabilities() [28]float64 {
+	var probs [28]float64
+	for a := 0; a < 10; a++ {
+		for b := 0; b < 10; b++ {
+			for c := 0; c < 10; c++ {
+				probs[a+b+c] += oc.Probability(a, b, c)
+			}
+		}
+	}
+	return probs
+}
+
+// WinProbability returns the exact probability that a bet wins
+func (s *GameService) WinProbability(betType string, betValue int) float64 {
+	oc := s.ExactOutcomeCounts()
+	var wins uint64
+	for a := 0; a < 10; a++ {
+		for b := 0; b < 10; b++ {
+			for c := 0; c < 10; c++ {
+				if s.CheckWin(betType, betValue, PC28Result{A: a, B: b, C: c, Sum: a + b + c}) {
+					wins += oc.Counts[a][b][c]
+				}
+			}
+		}
+	}
+	return float64(wins) / float64(oc.Total)
+}
+
+// BetRTP describes the theoretical return of one bet
+type BetRTP struct {
+	BetType     string  `json:"bet_type"`
+	BetValue    *int    `json:"bet_value,omitempty"` // Set for number and digit bets
+	Probability float64 `json:"probability"`
+	Odds        float64 `json:"odds"`
+	RTP         float64 `json:"rtp"`        // Probability * odds
+	HouseEdge   float64 `json:"house_edge"` // 1 - RTP
+}
+
+// OddsAnalysis is the full probability and RTP report
+type OddsAnalysis struct {
+	TotalDraws       uint64    `json:"total_draws"` // C(80,20)
+	SumProbabilities []float64 `json:"sum_probabilities"`
+	Bets             []BetRTP  `json:"bets"`
+}
+
+// AnalyzeOdds reports the exact win probability, RTP and house edge of every
+// bet under the given settings
+func (s *GameService) AnalyzeOdds(settings *GameSettings) *OddsAnalysis {
+	oc := s.ExactOutcomeCounts()
+	sums := oc.SumProbabilities()
+
+	analysis := &OddsAnalysis{
+		TotalDraws:       oc.Total,
+		SumProbabilities: sums[:],
+	}
+
+	addBet := func(betType string, value *int) {
+		betValue := 0
+		if value != nil {
+			betValue = *value
+		}
+		p := s.WinProbability(betType, betValue)
+		odds := settings.BetOdds(betType, betValue)
+		analysis.Bets = append(analysis.Bets, BetRTP{
+			BetType:     betType,
+			BetValue:    value,
+			Probability: p,
+			Odds:        odds,
+			RTP:         p * odds,
+			HouseEdge:   1 - p*odds,
+		})
+	}
+
+	for sum := 0; sum <= 27; sum++ {
+		v := sum
+		addBet("number", &v)
+	}
+
+	betTypes := make([]string, 0, len(settings.Odds))
+	for betType := range settings.Odds {
+		betTypes = append(betTypes, betType)
+	}
+	sort.Strings(betTypes)
+
+	for _, betType := range betTypes {
+		switch betType {
+		case "digit_a", "digit_b", "digit_c":
+			for d := 0; d <= 9; d++ {
+				v := d
+				addBet(betType, &v)
+			}
+		default:
+			addBet(betType, nil)
+		}
+	}
+
+	return analysis
+}
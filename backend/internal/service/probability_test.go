@@ -0,0 +1,87 @@
+package service
+
+import (
+	"math"
+	"testing"
+)
+
+// bruteForceCounts enumerates every draw of `drawn` numbers from 1..pool
+func bruteForceCounts(pool, drawn int) *OutcomeCounts {
+	gs := NewGameService()
+	out := &OutcomeCounts{}
+	picked := make([]int, 0, drawn)
+
+	var walk func(next int)
+	walk = func(next int) {
+		if len(picked) == drawn {
+			r := gs.CalculateResult(picked)
+			out.Counts[r.A][r.B][r.C]++
+			out.Total++
+			return
+		}
+		for v := next; v <= pool; v++ {
+			picked = append(picked, v)
+			walk(v + 1)
+			picked = picked[:len(picked)-1]
+		}
+	}
+	walk(1)
+	return out
+}
+
+func TestComputeOutcomeCountsMatchesBruteForce(t *testing.T) {
+	want := bruteForceCounts(22, 19)
+	got := computeOutcomeCounts(22, 19)
+
+	if got.Total != want.Total {
+		t.Fatalf("Total = %d, want %d", got.Total, want.Total)
+	}
+	if got.Counts != want.Counts {
+		t.Error("DP counts differ from brute-force enumeration")
+	}
+}
+
+func TestExactOutcomeCounts(t *testing.T) {
+	gs := NewGameService()
+	oc := gs.ExactOutcomeCounts()
+
+	// C(80,20)
+	if oc.Total != 3535316142212174320 {
+		t.Errorf("Total = %d, want C(80,20) = 3535316142212174320", oc.Total)
+	}
+
+	total := 0.0
+	for _, p := range oc.SumProbabilities() {
+		total += p
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("sum probabilities add up to %v, want 1", total)
+	}
+
+	big := gs.WinProbability("big", 0)
+	small := gs.WinProbability("small", 0)
+	if math.Abs(big+small-1) > 1e-9 {
+		t.Errorf("P(big) + P(small) = %v, want 1", big+small)
+	}
+}
+
+func TestAnalyzeOdds(t *testing.T) {
+	gs := NewGameService()
+	settings := gs.DefaultGameSettings()
+	analysis := gs.AnalyzeOdds(settings)
+
+	// 28 number bets + 3*10 digit bets + the remaining flat bet types
+	want := 28 + 30 + len(settings.Odds) - 3
+	if len(analysis.Bets) != want {
+		t.Fatalf("got %d bet entries, want %d", len(analysis.Bets), want)
+	}
+
+	for _, b := range analysis.Bets {
+		if b.RTP <= 0 || b.RTP > 1.2 {
+			t.Errorf("%s: implausible RTP %v", b.BetType, b.RTP)
+		}
+		if math.Abs(b.RTP+b.HouseEdge-1) > 1e-12 {
+			t.Errorf("%s: RTP + house edge != 1", b.BetType)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"pcgame/backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoomNotFound = errors.New("room not found")
+	ErrInvalidRoom  = errors.New("invalid room")
+)
+
+// RoomService looks up game rooms
+type RoomService struct {
+	db *gorm.DB
+}
+
+func NewRoomService(db *gorm.DB) *RoomService {
+	return &RoomService{db: db}
+}
+
+// EnsureDefaultRoom creates the default 1-minute room if it is missing and
+// attaches rounds created before rooms existed to it
+func (s *RoomService) EnsureDefaultRoom() (*model.GameRoom, error) {
+	room := model.GameRoom{
+		Code:     model.DefaultRoomCode,
+		Name:     "PC28 1分钟",
+		GameCode: model.GameCodePC28,
+		Status:   "active",
+	}
+	if err := s.db.Where("code = ?", model.DefaultRoomCode).FirstOrCreate(&room).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(&model.PC28Round{}).Where("room_id = 0").
+		Update("room_id", room.ID).Error; err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// GetByCode returns an active room; an empty code means the default room
+func (s *RoomService) GetByCode(code string) (*model.GameRoom, error) {
+	if code == "" {
+		code = model.DefaultRoomCode
+	}
+	var room model.GameRoom
+	if err := s.db.Where("code = ? AND status = ?", code, "active").First(&room).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoomNotFound
+		}
+		return nil, err
+	}
+	return &room, nil
+}
+
+// GetByID returns a room regardless of status
+func (s *RoomService) GetByID(id uint) (*model.GameRoom, error) {
+	var room model.GameRoom
+	if err := s.db.First(&room, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoomNotFound
+		}
+		return nil, err
+	}
+	return &room, nil
+}
+
+// GameForRound returns the room of a round and the game it runs
+func (s *RoomService) GameForRound(round *model.PC28Round) (*model.GameRoom, Game, error) {
+	room, err := s.GetByID(round.RoomID)
+	if err != nil {
+		return nil, nil, err
+	}
+	game, err := GetGame(room.GameCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	return room, game, nil
+}
+
+// ListActive returns all active rooms in display order
+func (s *RoomService) ListActive() ([]model.GameRoom, error) {
+	var rooms []model.GameRoom
+	err := s.db.Where("status = ?", "active").Order("sort_order asc, id asc").Find(&rooms).Error
+	return rooms, err
+}
+
+// Save validates a room against the global settings it overrides and persists it
+func (s *RoomService) Save(room *model.GameRoom) error {
+	if room.Status != "active" && room.Status != "disabled" {
+		return fmt.Errorf("%w: status must be active or disabled", ErrInvalidRoom)
+	}
+
+	// Issue numbers are unique across rooms, so prefixes must be too
+	var clash int64
+	s.db.Model(&model.GameRoom{}).Where("issue_prefix = ? AND id <> ?", room.IssuePrefix, room.ID).Count(&clash)
+	if clash > 0 {
+		return fmt.Errorf("%w: issue_prefix is already used by another room", ErrInvalidRoom)
+	}
+
+	if _, err := GetGame(room.GameCode); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRoom, err)
+	}
+
+	settings, err := NewSettingsService(s.db).GetForRoom(room)
+	if err != nil {
+		return err
+	}
+
+	var odds map[string]float64
+	if err := json.Unmarshal([]byte(room.Odds), &odds); err != nil {
+		return fmt.Errorf("%w: odds must be a JSON object", ErrInvalidRoom)
+	}
+	for betType := range odds {
+		if _, ok := settings.Odds[betType]; !ok {
+			return fmt.Errorf("%w: unknown bet type %s", ErrInvalidRoom, betType)
+		}
+	}
+	var numberOdds map[int]float64
+	if err := json.Unmarshal([]byte(room.NumberOdds), &numberOdds); err != nil {
+		return fmt.Errorf("%w: number_odds must be a JSON object", ErrInvalidRoom)
+	}
+	for sum := range numberOdds {
+		if _, ok := settings.NumberOdds[sum]; !ok {
+			return fmt.Errorf("%w: number odds must be for sums 0-27", ErrInvalidRoom)
+		}
+	}
+
+	if err := settings.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRoom, err)
+	}
+
+	return s.db.Save(room).Error
+}
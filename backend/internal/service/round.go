@@ -0,0 +1,240 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"pcgame/backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrRoundNotFound    = errors.New("round not found")
+	ErrRoundNotVoidable = errors.New("round is already settled or void")
+	ErrRoundNotSettled  = errors.New("round is not settled")
+	ErrInvalidKenoData  = errors.New("invalid keno data")
+)
+
+// RoundService handles round operations that touch bets and balances
+type RoundService struct {
+	db      *gorm.DB
+	gameSvc *GameService
+	roomSvc *RoomService
+	cycle   *RoundLifecycle
+}
+
+func NewRoundService(db *gorm.DB) *RoundService {
+	return &RoundService{
+		db:      db,
+		gameSvc: NewGameService(),
+		roomSvc: NewRoomService(db),
+		cycle:   NewRoundLifecycle(db),
+	}
+}
+
+// VoidResult summarizes a voided round
+type VoidResult struct {
+	Round          model.PC28Round `json:"round"`
+	RefundedBets   int64           `json:"refunded_bets"`
+	RefundedAmount model.Money     `json:"refunded_amount"`
+	UserIDs        []uint          `json:"-"` // Players whose balance changed
+}
+
+// VoidRound voids a round that has not been settled and refunds every
+// pending bet, all inside one transaction
+func (s *RoundService) VoidRound(roundID uint, reason string) (*VoidResult, error) {
+	var res VoidResult
+	var ev *RoundEvent
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&res.Round, roundID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRoundNotFound
+			}
+			return err
+		}
+
+		if !CanTransition(res.Round.Status, model.RoundStatusVoid) {
+			return ErrRoundNotVoidable
+		}
+
+		// Refund stakes per user
+		type userRefund struct {
+			UserID uint
+			Total  model.Money
+			Count  int64
+		}
+		var refunds []userRefund
+		if err := tx.Model(&model.PC28Bet{}).
+			Select("user_id, SUM(amount)::BIGINT as total, COUNT(*) as count").
+			Where("round_id = ? AND status = ?", roundID, model.BetStatusPending).
+			Group("user_id").
+			Scan(&refunds).Error; err != nil {
+			return err
+		}
+
+		for _, r := range refunds {
+			if err := Credit(tx, r.UserID, r.Total, LedgerEntry{
+				Type:    model.WalletTxRefund,
+				RoundID: &res.Round.ID,
+				Note:    reason,
+			}); err != nil {
+				return err
+			}
+			res.RefundedBets += r.Count
+			res.RefundedAmount += r.Total
+			res.UserIDs = append(res.UserIDs, r.UserID)
+		}
+
+		if err := tx.Model(&model.PC28Bet{}).
+			Where("round_id = ? AND status = ?", roundID, model.BetStatusPending).
+			Update("status", model.BetStatusRefunded).Error; err != nil {
+			return err
+		}
+
+		var err error
+		res.Round.VoidReason = reason
+		ev, err = s.cycle.TransitionTx(tx, &res.Round, model.RoundStatusVoid, map[string]interface{}{
+			"void_reason": reason,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	PublishRoundEvent(ev)
+	return &res, nil
+}
+
+// ResettleResult summarizes a corrected round
+type ResettleResult struct {
+	Round      model.PC28Round       `json:"round"`
+	Correction model.RoundCorrection `json:"correction"`
+	UserIDs    []uint                `json:"-"` // Players whose balance changed
+	Balances   map[uint]model.Money  `json:"-"` // Their balances after the correction
+}
+
+// ResettleRound replaces the draw of a settled round, reverses the previous
+// payouts and re-evaluates every bet against the corrected result. Balances
+// are adjusted by the difference, so a player may end up negative if the
+// reversed winnings were already spent.
+func (s *RoundService) ResettleRound(roundID uint, kenoData []int, reason string, adminID uint) (*ResettleResult, error) {
+	if err := ValidateKenoData(kenoData); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKenoData, err)
+	}
+
+	res := ResettleResult{Balances: make(map[uint]model.Money)}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&res.Round, roundID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRoundNotFound
+			}
+			return err
+		}
+
+		if res.Round.Status != model.RoundStatusSettled {
+			return ErrRoundNotSettled
+		}
+
+		_, game, err := s.roomSvc.GameForRound(&res.Round)
+		if err != nil {
+			return err
+		}
+		result := game.Result(kenoData)
+
+		res.Correction = model.RoundCorrection{
+			RoundID:     roundID,
+			AdminID:     adminID,
+			Reason:      reason,
+			OldKenoData: res.Round.KenoData,
+			OldResultA:  res.Round.ResultA,
+			OldResultB:  res.Round.ResultB,
+			OldResultC:  res.Round.ResultC,
+			OldSum:      res.Round.Sum,
+			NewKenoData: s.gameSvc.KenoDataToJSON(kenoData),
+			NewResultA:  result.A,
+			NewResultB:  result.B,
+			NewResultC:  result.C,
+			NewSum:      result.Sum,
+		}
+
+		var bets []model.PC28Bet
+		if err := tx.Where("round_id = ? AND status IN ?", roundID,
+			[]model.BetStatus{model.BetStatusWon, model.BetStatusLost}).
+			Find(&bets).Error; err != nil {
+			return err
+		}
+
+		deltas := make(map[uint]model.Money)
+		for _, bet := range bets {
+			oldWin := bet.WinAmount
+			res.Correction.OldPayout += oldWin
+
+			status := model.BetStatusLost
+			var newWin model.Money
+			if game.CheckWin(string(bet.BetType), bet.BetValue, result) {
+				status = model.BetStatusWon
+				newWin = bet.Amount.Mul(bet.Odds)
+			}
+			res.Correction.NewPayout += newWin
+
+			if status == bet.Status && newWin == oldWin {
+				continue
+			}
+			res.Correction.AffectedBets++
+			deltas[bet.UserID] += newWin - oldWin
+
+			if err := tx.Model(&bet).Updates(map[string]interface{}{
+				"status":     status,
+				"win_amount": newWin,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		for userID, delta := range deltas {
+			if delta == 0 {
+				continue
+			}
+			if err := Adjust(tx, userID, delta, LedgerEntry{
+				Type:    model.WalletTxCorrection,
+				RoundID: &res.Round.ID,
+				Note:    reason,
+			}); err != nil {
+				return err
+			}
+			var balance model.Money
+			if err := readBalance(tx, userID, &balance); err != nil {
+				return err
+			}
+			res.UserIDs = append(res.UserIDs, userID)
+			res.Balances[userID] = balance
+		}
+
+		res.Round.KenoData = res.Correction.NewKenoData
+		res.Round.ResultA = result.A
+		res.Round.ResultB = result.B
+		res.Round.ResultC = result.C
+		res.Round.Sum = result.Sum
+		if err := tx.Model(&res.Round).Updates(map[string]interface{}{
+			"keno_data": res.Round.KenoData,
+			"result_a":  result.A,
+			"result_b":  result.B,
+			"result_c":  result.C,
+			"sum":       result.Sum,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&res.Correction).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"pcgame/backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidSettings is returned when settings fail validation
+var ErrInvalidSettings = errors.New("invalid settings")
+
+// settingsID is the primary key of the single game settings row
+const settingsID = 1
+
+// providerDrawInterval is the Keno provider's draw cadence in seconds while
+// rounds are drawn from it, and 0 otherwise
+var providerDrawInterval int
+
+// SetProviderDrawInterval requires round durations to be a multiple of the
+// provider's draw interval, so that every round of a room is drawn from a
+// provider draw of its own; 0 lifts the requirement. Call it at startup.
+func SetProviderDrawInterval(seconds int) {
+	providerDrawInterval = seconds
+}
+
+// GameSettings are the runtime game parameters editable by admins
+type GameSettings struct {
+	RoundDuration int                `json:"round_duration"` // 轮次时长 (秒)
+	BettingWindow int                `json:"betting_window"` // 投注窗口 (秒)
+	BetCutoff     int                `json:"bet_cutoff"`     // 截止前停止投注的安全余量 (秒)
+	BetCancel     bool               `json:"bet_cancel"`     // 是否允许玩家撤单
+	CancelLimit   int                `json:"cancel_limit"`   // 每位玩家每日撤单次数上限, 0 不限
+	MinBet        model.Money        `json:"min_bet"`
+	MaxBet        model.Money        `json:"max_bet"`
+	Odds          map[string]float64 `json:"odds"`
+	NumberOdds    map[int]float64    `json:"number_odds"`
+}
+
+// DefaultGameSettings returns the built-in settings used until an admin saves some
+func (s *GameService) DefaultGameSettings() *GameSettings {
+	settings := &GameSettings{
+		RoundDuration: 60,
+		BettingWindow: 55,
+		BetCutoff:     2,
+		BetCancel:     true,
+		MinBet:        1 * model.MoneyScale,
+		MaxBet:        100000 * model.MoneyScale,
+		Odds:          s.GetOdds(),
+		NumberOdds:    s.GetNumberOdds(),
+	}
+	// A slower provider sets the pace: one round per draw
+	if providerDrawInterval > settings.RoundDuration {
+		settings.RoundDuration = providerDrawInterval
+		settings.BettingWindow = providerDrawInterval - 5
+	}
+	return settings
+}
+
+// BetOdds returns the odds for a specific bet, or 0 if the bet is invalid
+func (gs *GameSettings) BetOdds(betType string, betValue int) float64 {
+	if betType == "number" {
+		return gs.NumberOdds[betValue]
+	}
+	return gs.Odds[betType]
+}
+
+// BetDeadline returns the last instant a bet is accepted for a round closing at closeTime
+func (gs *GameSettings) BetDeadline(closeTime time.Time) time.Time {
+	return closeTime.Add(-time.Duration(gs.BetCutoff) * time.Second)
+}
+
+// Validate checks that the settings are consistent
+func (gs *GameSettings) Validate() error {
+	if gs.RoundDuration < 10 {
+		return fmt.Errorf("round_duration must be at least 10 seconds")
+	}
+	// Rounds sit on a daily grid, so a day must hold a whole number of them
+	if 86400%gs.RoundDuration != 0 {
+		return fmt.Errorf("round_duration must divide a day (86400 seconds) evenly")
+	}
+	// Shorter rounds would share a provider draw and all get the same result
+	if providerDrawInterval > 0 && gs.RoundDuration%providerDrawInterval != 0 {
+		return fmt.Errorf("round_duration must be a multiple of the Keno provider's draw interval (%d seconds)", providerDrawInterval)
+	}
+	if gs.BettingWindow < 5 || gs.BettingWindow >= gs.RoundDuration {
+		return fmt.Errorf("betting_window must be at least 5 seconds and shorter than round_duration")
+	}
+	if gs.BetCutoff < 0 || gs.BetCutoff >= gs.BettingWindow {
+		return fmt.Errorf("bet_cutoff must be non-negative and shorter than betting_window")
+	}
+	if gs.CancelLimit < 0 {
+		return fmt.Errorf("cancel_limit must be non-negative")
+	}
+	if gs.MinBet <= 0 || gs.MaxBet < gs.MinBet {
+		return fmt.Errorf("min_bet must be positive and not exceed max_bet")
+	}
+	for betType, odds := range gs.Odds {
+		if odds <= 1 {
+			return fmt.Errorf("odds for %s must be greater than 1", betType)
+		}
+	}
+	// Only games with a per-sum table (PC28) carry number odds
+	if len(gs.NumberOdds) > 0 {
+		for sum := 0; sum <= 27; sum++ {
+			if gs.NumberOdds[sum] <= 1 {
+				return fmt.Errorf("number odds for %d must be greater than 1", sum)
+			}
+		}
+	}
+	return nil
+}
+
+// SettingsService loads and saves game settings
+type SettingsService struct {
+	db      *gorm.DB
+	gameSvc *GameService
+}
+
+func NewSettingsService(db *gorm.DB) *SettingsService {
+	return &SettingsService{db: db, gameSvc: NewGameService()}
+}
+
+// Get returns the current settings, falling back to defaults for anything unsaved
+func (s *SettingsService) Get() (*GameSettings, error) {
+	settings := s.gameSvc.DefaultGameSettings()
+
+	var row model.GameSetting
+	if err := s.db.First(&row, settingsID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return settings, nil
+		}
+		return nil, err
+	}
+
+	settings.RoundDuration = row.RoundDuration
+	settings.BettingWindow = row.BettingWindow
+	settings.BetCutoff = row.BetCutoff
+	if row.BetCancel != nil {
+		settings.BetCancel = *row.BetCancel
+	}
+	settings.CancelLimit = row.CancelLimit
+	settings.MinBet = row.MinBet
+	settings.MaxBet = row.MaxBet
+
+	// Saved odds override defaults key by key, so new bet types keep a price
+	var odds map[string]float64
+	if row.Odds != "" && json.Unmarshal([]byte(row.Odds), &odds) == nil {
+		for betType, v := range odds {
+			if _, ok := settings.Odds[betType]; ok {
+				settings.Odds[betType] = v
+			}
+		}
+	}
+	var numberOdds map[int]float64
+	if row.NumberOdds != "" && json.Unmarshal([]byte(row.NumberOdds), &numberOdds) == nil {
+		for sum, v := range numberOdds {
+			if _, ok := settings.NumberOdds[sum]; ok {
+				settings.NumberOdds[sum] = v
+			}
+		}
+	}
+
+	return settings, nil
+}
+
+// GetForRoom returns the settings with the room's cadence and odds overrides applied
+// The saved odds are PC28's; rooms running another game start from that game's default odds
+func (s *SettingsService) GetForRoom(room *model.GameRoom) (*GameSettings, error) {
+	game, err := GetGame(room.GameCode)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.Get()
+	if err != nil {
+		return nil, err
+	}
+	if game.Code() != model.GameCodePC28 {
+		defaults := game.DefaultSettings()
+		settings.Odds = defaults.Odds
+		settings.NumberOdds = defaults.NumberOdds
+	}
+
+	settings.ApplyRoom(room)
+	return settings, nil
+}
+
+// ApplyRoom overrides cadence and odds with the room's own values where set
+func (gs *GameSettings) ApplyRoom(room *model.GameRoom) {
+	if room.RoundDuration > 0 {
+		gs.RoundDuration = room.RoundDuration
+	}
+	if room.BettingWindow > 0 {
+		gs.BettingWindow = room.BettingWindow
+	}
+
+	var odds map[string]float64
+	if room.Odds != "" && json.Unmarshal([]byte(room.Odds), &odds) == nil {
+		for betType, v := range odds {
+			if _, ok := gs.Odds[betType]; ok {
+				gs.Odds[betType] = v
+			}
+		}
+	}
+	var numberOdds map[int]float64
+	if room.NumberOdds != "" && json.Unmarshal([]byte(room.NumberOdds), &numberOdds) == nil {
+		for sum, v := range numberOdds {
+			if _, ok := gs.NumberOdds[sum]; ok {
+				gs.NumberOdds[sum] = v
+			}
+		}
+	}
+}
+
+// Save validates and persists settings
+func (s *SettingsService) Save(settings *GameSettings, adminID uint) error {
+	if err := settings.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSettings, err)
+	}
+
+	odds, _ := json.Marshal(settings.Odds)
+	numberOdds, _ := json.Marshal(settings.NumberOdds)
+
+	var row model.GameSetting
+	if err := s.db.First(&row, settingsID).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		row.ID = settingsID
+	}
+
+	row.RoundDuration = settings.RoundDuration
+	row.BettingWindow = settings.BettingWindow
+	row.BetCutoff = settings.BetCutoff
+	row.BetCancel = &settings.BetCancel
+	row.CancelLimit = settings.CancelLimit
+	row.MinBet = settings.MinBet
+	row.MaxBet = settings.MaxBet
+	row.Odds = string(odds)
+	row.NumberOdds = string(numberOdds)
+	row.UpdatedByID = &adminID
+
+	return s.db.Save(&row).Error
+}
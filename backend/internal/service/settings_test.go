@@ -0,0 +1,90 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGameSettingsValidate(t *testing.T) {
+	gs := NewGameService()
+
+	if err := gs.DefaultGameSettings().Validate(); err != nil {
+		t.Fatalf("default settings invalid: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(s *GameSettings)
+	}{
+		{"window longer than round", func(s *GameSettings) { s.BettingWindow = s.RoundDuration }},
+		{"round too short", func(s *GameSettings) { s.RoundDuration = 5 }},
+		{"round not dividing a day", func(s *GameSettings) { s.RoundDuration = 70 }},
+		{"negative cutoff", func(s *GameSettings) { s.BetCutoff = -1 }},
+		{"cutoff covers window", func(s *GameSettings) { s.BetCutoff = s.BettingWindow }},
+		{"negative cancel limit", func(s *GameSettings) { s.CancelLimit = -1 }},
+		{"min above max", func(s *GameSettings) { s.MinBet = s.MaxBet + 1 }},
+		{"odds not above 1", func(s *GameSettings) { s.Odds["big"] = 1 }},
+		{"missing number odds", func(s *GameSettings) { delete(s.NumberOdds, 27) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := gs.DefaultGameSettings()
+			tt.modify(s)
+			if err := s.Validate(); err == nil {
+				t.Error("Validate() expected error")
+			}
+		})
+	}
+}
+
+func TestGameSettingsBetOdds(t *testing.T) {
+	s := NewGameService().DefaultGameSettings()
+	s.Odds["big"] = 1.9
+	s.NumberOdds[13] = 12
+
+	if got := s.BetOdds("big", 0); got != 1.9 {
+		t.Errorf("BetOdds(big) = %v, want 1.9", got)
+	}
+	if got := s.BetOdds("number", 13); got != 12 {
+		t.Errorf("BetOdds(number, 13) = %v, want 12", got)
+	}
+	if got := s.BetOdds("number", 30); got != 0 {
+		t.Errorf("BetOdds(number, 30) = %v, want 0", got)
+	}
+}
+
+func TestGameSettingsBetDeadline(t *testing.T) {
+	s := NewGameService().DefaultGameSettings()
+	s.BetCutoff = 3
+
+	closeTime := time.Date(2026, 1, 22, 12, 0, 55, 0, time.UTC)
+	want := time.Date(2026, 1, 22, 12, 0, 52, 0, time.UTC)
+	if got := s.BetDeadline(closeTime); !got.Equal(want) {
+		t.Errorf("BetDeadline() = %v, want %v", got, want)
+	}
+}
+
+func TestGameSettingsFollowProviderDrawInterval(t *testing.T) {
+	SetProviderDrawInterval(300)
+	t.Cleanup(func() { SetProviderDrawInterval(0) })
+
+	s := NewGameService().DefaultGameSettings()
+	if s.RoundDuration != 300 {
+		t.Errorf("default round_duration = %d, want 300", s.RoundDuration)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("default settings invalid: %v", err)
+	}
+
+	for _, d := range []int{60, 240, 450} {
+		s.RoundDuration = d
+		if err := s.Validate(); err == nil {
+			t.Errorf("Validate() with round_duration %d expected error", d)
+		}
+	}
+	s.RoundDuration = 600
+	if err := s.Validate(); err != nil {
+		t.Errorf("Validate() with round_duration 600: %v", err)
+	}
+}
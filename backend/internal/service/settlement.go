@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+
+	"pcgame/backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRoundNotSettleable is returned when a round is not closed with a draw
+var ErrRoundNotSettleable = errors.New("round is not closed and drawn")
+
+// SettleResult summarizes a settled round
+type SettleResult struct {
+	Round    model.PC28Round `json:"round"`
+	WonBets  int64           `json:"won_bets"`
+	LostBets int64           `json:"lost_bets"`
+	Payout   model.Money     `json:"payout"`
+	UserIDs  []uint          `json:"-"` // Players who were credited
+}
+
+// betKey identifies the bets of a round that share an outcome
+type betKey struct {
+	BetType  string
+	BetValue int
+}
+
+// winningKeys returns the keys that win against result, as (bet_type,
+// bet_value) tuples for an IN clause
+func winningKeys(game Game, result DrawResult, keys []betKey) [][]interface{} {
+	var winning [][]interface{}
+	for _, k := range keys {
+		if game.CheckWin(k.BetType, k.BetValue, result) {
+			winning = append(winning, []interface{}{k.BetType, k.BetValue})
+		}
+	}
+	return winning
+}
+
+// BetPayout is what a winning bet pays: amount × odds rounded down to the
+// cent. SettleRound computes the same in SQL as amount * odds / RateScale.
+func BetPayout(amount model.Money, odds model.Rate) model.Money {
+	return amount.Mul(odds)
+}
+
+// SettleRound settles every pending bet of a drawn round in one transaction.
+//
+// Outcomes are decided once per distinct (bet_type, bet_value), then applied
+// with set-based conditional updates on status = 'pending', so each bet is
+// settled and paid at most once however often or concurrently this runs.
+// A winning bet pays amount × odds rounded down to the cent, the same integer
+// division as model.Money.Mul.
+// Winnings are credited per user in a single statement that also writes each
+// player's payout entry to the wallet ledger, and the round only becomes
+// settled when the same transaction has processed all its bets.
+func (s *RoundService) SettleRound(roundID uint) (*SettleResult, error) {
+	var res SettleResult
+	var ev *RoundEvent
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Serializes settlement, voids and corrections of this round
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&res.Round, roundID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRoundNotFound
+			}
+			return err
+		}
+
+		if res.Round.Status != model.RoundStatusClosed || res.Round.DrawnAt == nil {
+			return ErrRoundNotSettleable
+		}
+
+		_, game, err := s.roomSvc.GameForRound(&res.Round)
+		if err != nil {
+			return err
+		}
+		result := StoredResult(&res.Round)
+
+		// Decide each distinct bet once
+		var keys []betKey
+		if err := tx.Model(&model.PC28Bet{}).
+			Distinct("bet_type", "bet_value").
+			Where("round_id = ? AND status = ?", roundID, model.BetStatusPending).
+			Scan(&keys).Error; err != nil {
+			return err
+		}
+		winning := winningKeys(game, result, keys)
+
+		if len(winning) > 0 {
+			type credit struct {
+				UserID uint
+				Total  model.Money
+				Count  int64
+			}
+			var credits []credit
+			if err := tx.Raw(`
+				WITH won AS (
+					UPDATE pc28_bets SET status = ?, win_amount = amount * odds / ?, updated_at = NOW()
+					WHERE round_id = ? AND status = ? AND deleted_at IS NULL AND (bet_type, bet_value) IN ?
+					RETURNING user_id, win_amount
+				), totals AS (
+					SELECT user_id, SUM(win_amount)::BIGINT AS total, COUNT(*) AS count FROM won GROUP BY user_id
+				), paid AS (
+					UPDATE users SET balance = users.balance + totals.total, updated_at = NOW()
+					FROM totals WHERE users.id = totals.user_id
+					RETURNING users.id, users.balance
+				), ledger AS (
+					INSERT INTO wallet_transactions (created_at, updated_at, user_id, type, amount, balance_after, round_id)
+					SELECT NOW(), NOW(), paid.id, ?, totals.total, paid.balance, ?
+					FROM paid JOIN totals ON totals.user_id = paid.id
+					RETURNING user_id
+				)
+				SELECT totals.user_id, totals.total, totals.count FROM totals JOIN ledger ON ledger.user_id = totals.user_id`,
+				model.BetStatusWon, model.RateScale, roundID, model.BetStatusPending, winning, model.WalletTxPayout, roundID).
+				Scan(&credits).Error; err != nil {
+				return err
+			}
+
+			for _, c := range credits {
+				res.WonBets += c.Count
+				res.Payout += c.Total
+				res.UserIDs = append(res.UserIDs, c.UserID)
+			}
+		}
+
+		lost := tx.Model(&model.PC28Bet{}).
+			Where("round_id = ? AND status = ?", roundID, model.BetStatusPending).
+			Updates(map[string]interface{}{"status": model.BetStatusLost, "win_amount": 0})
+		if lost.Error != nil {
+			return lost.Error
+		}
+		res.LostBets = lost.RowsAffected
+
+		ev, err = s.cycle.TransitionTx(tx, &res.Round, model.RoundStatusSettled, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	PublishRoundEvent(ev)
+	return &res, nil
+}
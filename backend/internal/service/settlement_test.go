@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing"
+
+	"pcgame/backend/internal/model"
+)
+
+func TestWinningKeys(t *testing.T) {
+	game := NewPC28Game()
+	result := DrawResult{A: 4, B: 5, C: 5, Sum: 14}
+
+	keys := []betKey{
+		{"big", 0},
+		{"small", 0},
+		{"even", 0},
+		{"odd", 0},
+		{"big_even", 0},
+		{"number", 14},
+		{"number", 13},
+		{"leopard", 0},
+	}
+	want := map[betKey]bool{
+		{"big", 0}:      true,
+		{"even", 0}:     true,
+		{"big_even", 0}: true,
+		{"number", 14}:  true,
+	}
+
+	got := winningKeys(game, result, keys)
+	if len(got) != len(want) {
+		t.Fatalf("winningKeys = %v, want %d winners", got, len(want))
+	}
+	for _, tuple := range got {
+		k := betKey{tuple[0].(string), tuple[1].(int)}
+		if !want[k] {
+			t.Errorf("%v should not win against sum %d", k, result.Sum)
+		}
+	}
+
+	if got := winningKeys(game, result, nil); len(got) != 0 {
+		t.Errorf("winningKeys(nil) = %v, want none", got)
+	}
+}
+
+func TestBetPayout(t *testing.T) {
+	tests := []struct {
+		amount model.Money
+		odds   model.Rate
+		want   model.Money
+	}{
+		{1000, 19500, 1950},     // 10.00 × 1.95
+		{333, 19500, 649},       // 6.4935 rounds down
+		{1, 19999, 1},           // 0.019999 rounds down
+		{105, 98000, 1029},      // 1.05 × 9.8
+		{100000, 10001, 100010}, // odds just above 1
+	}
+	for _, tt := range tests {
+		got := BetPayout(tt.amount, tt.odds)
+		if got != tt.want {
+			t.Errorf("BetPayout(%s, %v) = %s, want %s", tt.amount, tt.odds.Float(), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"pcgame/backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// LedgerEntry describes why a balance changes; the amount and the resulting
+// balance are filled in when it is posted
+type LedgerEntry struct {
+	Type    model.WalletTxType
+	BetID   *uint
+	RoundID *uint
+	RefType string
+	RefID   *uint
+	Note    string
+}
+
+// EnsureOpeningBalances gives every player who has a balance but no ledger
+// entry yet an opening entry for it, so each ledger adds up to its balance.
+// Safe to run on every start and from several instances at once.
+func EnsureOpeningBalances(db *gorm.DB) (int64, error) {
+	var created int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('wallet_opening_balances'))").Error; err != nil {
+			return err
+		}
+		res := tx.Exec(`
+			INSERT INTO wallet_transactions (created_at, updated_at, user_id, type, amount, balance_after, note)
+			SELECT NOW(), NOW(), u.id, ?, u.balance, u.balance, 'Balance before the wallet ledger'
+			FROM users u
+			WHERE u.deleted_at IS NULL AND u.balance <> 0
+			  AND NOT EXISTS (SELECT 1 FROM wallet_transactions w WHERE w.user_id = u.id)`,
+			model.WalletTxOpening)
+		created = res.RowsAffected
+		return res.Error
+	})
+	return created, err
+}
+
+// ErrNegativeAmount is returned when Debit or Credit is given a negative
+// amount, which would move the balance the other way
+var ErrNegativeAmount = errors.New("amount must not be negative")
+
+// Debit takes an amount from a player's balance, failing rather than going
+// negative, and records it in the ledger
+func Debit(tx *gorm.DB, userID uint, amount model.Money, entry LedgerEntry) error {
+	if amount < 0 {
+		return fmt.Errorf("%w: debit of %s", ErrNegativeAmount, amount)
+	}
+	return post(tx, userID, -amount, false, entry)
+}
+
+// Credit adds an amount to a player's balance and records it in the ledger
+func Credit(tx *gorm.DB, userID uint, amount model.Money, entry LedgerEntry) error {
+	if amount < 0 {
+		return fmt.Errorf("%w: credit of %s", ErrNegativeAmount, amount)
+	}
+	return post(tx, userID, amount, true, entry)
+}
+
+// Adjust changes a player's balance by a signed amount that may leave it
+// negative, such as reversed winnings, and records it in the ledger
+func Adjust(tx *gorm.DB, userID uint, delta model.Money, entry LedgerEntry) error {
+	return post(tx, userID, delta, true, entry)
+}
+
+// Record writes a ledger entry that leaves the balance unchanged, for a step
+// worth tracing that moves no money, such as a held withdrawal being paid out
+func Record(tx *gorm.DB, userID uint, entry LedgerEntry) error {
+	return post(tx, userID, 0, true, entry)
+}
+
+// post applies a balance change and writes its ledger entry in the caller's
+// transaction. The check and the update are one statement, and the entry
+// carries the balance it produced.
+func post(tx *gorm.DB, userID uint, delta model.Money, allowNegative bool, entry LedgerEntry) error {
+	cond := "id = ?"
+	args := []interface{}{delta, userID}
+	if !allowNegative {
+		cond += " AND balance + ? >= 0"
+		args = append(args, delta)
+	}
+
+	var balances []model.Money
+	if err := tx.Raw("UPDATE users SET balance = balance + ?, updated_at = NOW() WHERE "+cond+" RETURNING balance",
+		args...).Scan(&balances).Error; err != nil {
+		return err
+	}
+	if len(balances) == 0 {
+		var count int64
+		if err := tx.Model(&model.User{}).Where("id = ?", userID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrUserNotFound
+		}
+		return ErrInsufficientBalance
+	}
+
+	return tx.Create(&model.WalletTransaction{
+		UserID:       userID,
+		Type:         entry.Type,
+		Amount:       delta,
+		BalanceAfter: balances[0],
+		BetID:        entry.BetID,
+		RoundID:      entry.RoundID,
+		RefType:      entry.RefType,
+		RefID:        entry.RefID,
+		Note:         entry.Note,
+	}).Error
+}
@@ -0,0 +1,42 @@
+package tasks
+
+import (
+	"time"
+
+	"pcgame/backend/internal/service"
+)
+
+// commissionLookbackDays is how many past days each settlement pass covers,
+// so days missed during downtime, days with bets still pending, and bets
+// voided or re-settled since are all picked up later
+const commissionLookbackDays = 7
+
+// settleCommissions settles or revises referral commissions for the recent
+// days and pushes the new balance to credited referrers
+func (s *Scheduler) settleCommissions() {
+	if !s.isLeader() || !s.commMu.TryLock() {
+		return
+	}
+	defer s.commMu.Unlock()
+
+	today := time.Now()
+	for i := commissionLookbackDays; i >= 1; i-- {
+		day := today.AddDate(0, 0, -i)
+		results, err := s.commSvc.SettleDay(day)
+		if err != nil {
+			s.logger.Errorf("Failed to settle referral commissions for %s: %v", day.Format(service.CommissionDateLayout), err)
+		}
+
+		var paid int
+		for _, res := range results {
+			if res.Credited {
+				paid++
+				s.hub.SendBalance(res.Commission.ReferrerID, res.Balance, "commission")
+			}
+		}
+		if len(results) > 0 {
+			s.logger.Infof("Settled %d referral commissions for %s (%d credited)",
+				len(results), day.Format(service.CommissionDateLayout), paid)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package tasks
+
+import (
+	"pcgame/backend/internal/model"
+	"pcgame/backend/internal/service"
+	"pcgame/backend/internal/websocket"
+)
+
+// broadcastRoundEvent pushes a round transition to the room's subscribers
+// Voids are announced by whoever voided the round, with the refund totals
+func (s *Scheduler) broadcastRoundEvent(ev service.RoundEvent) {
+	if ev.To == model.RoundStatusVoid {
+		return
+	}
+
+	room, err := s.roomSvc.GetByID(ev.Round.RoomID)
+	if err != nil {
+		s.logger.Errorf("Failed to load room for round %s: %v", ev.Round.IssueNumber, err)
+		return
+	}
+
+	s.hub.BroadcastRoundUpdate(websocket.RoomTopic(room.Code), map[string]interface{}{
+		"room":             room.Code,
+		"game":             room.GameCode,
+		"round_id":         ev.Round.ID,
+		"issue_number":     ev.Round.IssueNumber,
+		"open_time":        ev.Round.OpenTime,
+		"close_time":       ev.Round.CloseTime,
+		"status":           ev.To,
+		"previous_status":  ev.From,
+		"server_seed_hash": ev.Round.ServerSeedHash,
+	})
+}
@@ -0,0 +1,191 @@
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// LeaderElector keeps a single scheduler leader across instances with a
+// Postgres session advisory lock.
+//
+// The lock is held on a dedicated connection. Postgres releases it when that
+// session ends, so a crashed leader frees it for the others. While leading,
+// the lease is renewed every interval by confirming the session still holds
+// the lock; if that does not succeed within the interval the instance steps
+// down rather than risk two leaders. A session that ran the lock query is
+// never returned to the pool: it is unlocked and then discarded, so neither
+// the lock nor its session settings outlive it in an idle connection.
+type LeaderElector struct {
+	db       *gorm.DB
+	logger   *zap.SugaredLogger
+	key      int64
+	interval time.Duration
+
+	mu     sync.RWMutex
+	conn   *sql.Conn
+	leader bool
+
+	onElected func() // Runs synchronously after the lock is won
+}
+
+// NewLeaderElector creates a leader elector
+func NewLeaderElector(db *gorm.DB, logger *zap.SugaredLogger, key int64, interval time.Duration, onElected func()) *LeaderElector {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	return &LeaderElector{
+		db:        db,
+		logger:    logger,
+		key:       key,
+		interval:  interval,
+		onElected: onElected,
+	}
+}
+
+// IsLeader reports whether this instance currently holds the lock
+func (e *LeaderElector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leader
+}
+
+// Run campaigns for leadership and renews the lease until ctx is done
+func (e *LeaderElector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		if e.IsLeader() {
+			if err := e.renew(ctx); err != nil {
+				e.logger.Warnf("Scheduler leader lease lost: %v", err)
+				e.stepDown()
+			}
+		} else if err := e.campaign(ctx); err != nil {
+			e.logger.Errorf("Scheduler leader election failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			e.release()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// campaign tries to take the lock without blocking
+func (e *LeaderElector) campaign(ctx context.Context) error {
+	sqlDB, err := e.db.DB()
+	if err != nil {
+		return err
+	}
+
+	cctx, cancel := context.WithTimeout(ctx, e.interval)
+	defer cancel()
+
+	conn, err := sqlDB.Conn(cctx)
+	if err != nil {
+		return err
+	}
+
+	// Let Postgres notice a vanished leader host within seconds, not hours
+	if _, err := conn.ExecContext(cctx,
+		"SET tcp_keepalives_idle = 10; SET tcp_keepalives_interval = 5; SET tcp_keepalives_count = 3"); err != nil {
+		e.logger.Warnf("Failed to set keepalives on leader session: %v", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(cctx, "SELECT pg_try_advisory_lock($1)", e.key).Scan(&acquired); err != nil {
+		e.discard(conn) // The lock may have been granted before the query failed
+		return err
+	}
+	if !acquired {
+		e.discard(conn)
+		return nil
+	}
+
+	e.mu.Lock()
+	e.conn = conn
+	e.leader = true
+	e.mu.Unlock()
+
+	e.logger.Infof("Elected scheduler leader (lock %d)", e.key)
+	if e.onElected != nil {
+		e.onElected()
+	}
+	return nil
+}
+
+// renew confirms that the leader session is alive and still holds the lock
+func (e *LeaderElector) renew(ctx context.Context) error {
+	e.mu.RLock()
+	conn := e.conn
+	e.mu.RUnlock()
+
+	cctx, cancel := context.WithTimeout(ctx, e.interval)
+	defer cancel()
+
+	// A bigint advisory key is stored as classid (high 32 bits) and objid (low 32 bits)
+	var held bool
+	err := conn.QueryRowContext(cctx, `SELECT EXISTS (
+		SELECT 1 FROM pg_locks
+		WHERE locktype = 'advisory' AND pid = pg_backend_pid() AND granted
+		  AND classid = $1 AND objid = $2 AND objsubid = 1)`,
+		uint32(uint64(e.key)>>32), uint32(e.key)).Scan(&held)
+	if err != nil {
+		return err
+	}
+	if !held {
+		return errors.New("advisory lock no longer held")
+	}
+	return nil
+}
+
+// stepDown gives up leadership and drops the session so the lock is released
+func (e *LeaderElector) stepDown() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn != nil {
+		e.discard(e.conn)
+		e.conn = nil
+	}
+	e.leader = false
+}
+
+// discard unlocks what the session may hold, best effort, and closes the
+// connection for good. sql.Conn.Close alone would hand it back to the pool
+// with the lock still held; reporting it bad makes database/sql drop it and
+// end the session.
+func (e *LeaderElector) discard(conn *sql.Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.interval)
+	defer cancel()
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock_all()"); err != nil {
+		e.logger.Warnf("Failed to unlock scheduler leader session: %v", err)
+	}
+
+	conn.Raw(func(any) error { return driver.ErrBadConn })
+	conn.Close()
+}
+
+// release unlocks explicitly on shutdown so a follower can take over at once
+func (e *LeaderElector) release() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		return
+	}
+
+	e.discard(e.conn)
+	e.conn = nil
+	e.leader = false
+	e.logger.Info("Released scheduler leadership")
+}
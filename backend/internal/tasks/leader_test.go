@@ -0,0 +1,66 @@
+package tasks
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// openTestDB connects to the Postgres named by TEST_DATABASE_DSN, skipping
+// the test when it is not set
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestLeaderStepDownFreesLock(t *testing.T) {
+	const key = 280099
+	ctx := context.Background()
+	logger := zap.NewNop().Sugar()
+
+	// Each instance gets its own pool, as separate replicas would
+	first := NewLeaderElector(openTestDB(t), logger, key, time.Second, nil)
+	second := NewLeaderElector(openTestDB(t), logger, key, time.Second, nil)
+	defer first.release()
+	defer second.release()
+
+	if err := first.campaign(ctx); err != nil || !first.IsLeader() {
+		t.Fatalf("first campaign: leader = %v, err = %v", first.IsLeader(), err)
+	}
+	if err := second.campaign(ctx); err != nil || second.IsLeader() {
+		t.Fatalf("second campaign while first leads: leader = %v, err = %v", second.IsLeader(), err)
+	}
+
+	first.stepDown()
+
+	if err := second.campaign(ctx); err != nil || !second.IsLeader() {
+		t.Fatalf("second campaign after step down: leader = %v, err = %v", second.IsLeader(), err)
+	}
+
+	// The losing campaign left nothing behind in the first instance's pool either
+	if err := first.campaign(ctx); err != nil || first.IsLeader() {
+		t.Fatalf("first campaign while second leads: leader = %v, err = %v", first.IsLeader(), err)
+	}
+	second.stepDown()
+	if err := first.campaign(ctx); err != nil || !first.IsLeader() {
+		t.Fatalf("first campaign after second stepped down: leader = %v, err = %v", first.IsLeader(), err)
+	}
+}
@@ -0,0 +1,137 @@
+package tasks
+
+import (
+	"encoding/json"
+	"time"
+
+	"pcgame/backend/internal/model"
+	"pcgame/backend/internal/websocket"
+)
+
+// Recovery policies for rounds left stale by downtime
+const (
+	RecoveryPolicySettle = "settle" // Draw and settle; void if the draw is unavailable
+	RecoveryPolicyVoid   = "void"   // Void and refund every stale round
+)
+
+// RecoveredRound records what recovery did with one stale round
+type RecoveredRound struct {
+	RoundID     uint   `json:"round_id"`
+	IssueNumber string `json:"issue_number"`
+	Status      string `json:"status"` // Status found at startup
+	Action      string `json:"action"` // settled, voided, failed
+	Error       string `json:"error,omitempty"`
+}
+
+// Recover finds every round that closed longer than the grace period ago but
+// is still pending, open, or closed and unsettled, and resolves it by the
+// configured policy. Rounds closed more recently are left to the normal
+// draw and settle loop, so a provider that is merely late (for example at a
+// leader takeover) does not get them voided. The summary is logged and
+// stored as a RecoveryRun for admins.
+func (s *Scheduler) Recover() *model.RecoveryRun {
+	s.roundMu.Lock()
+	defer s.roundMu.Unlock()
+
+	policy := s.policy
+	if policy != RecoveryPolicySettle && policy != RecoveryPolicyVoid {
+		s.logger.Warnf("Unknown recovery policy %q, using %s", policy, RecoveryPolicySettle)
+		policy = RecoveryPolicySettle
+	}
+
+	run := model.RecoveryRun{Policy: policy, StartedAt: time.Now()}
+
+	var rounds []model.PC28Round
+	s.db.Where("status IN ? AND close_time <= ?",
+		[]model.RoundStatus{model.RoundStatusPending, model.RoundStatusOpen, model.RoundStatusClosed},
+		run.StartedAt.Add(-s.grace)).
+		Order("id asc").
+		Find(&rounds)
+
+	details := make([]RecoveredRound, 0, len(rounds))
+	for i := range rounds {
+		round := &rounds[i]
+		rec := RecoveredRound{
+			RoundID:     round.ID,
+			IssueNumber: round.IssueNumber,
+			Status:      string(round.Status),
+		}
+
+		var err error
+		if policy == RecoveryPolicyVoid {
+			err = s.voidStaleRound(round, "Voided after server downtime")
+			rec.Action = "voided"
+		} else {
+			rec.Action, err = s.settleStaleRound(round)
+		}
+		if err != nil {
+			rec.Action = "failed"
+			rec.Error = err.Error()
+		}
+
+		switch rec.Action {
+		case "settled":
+			run.Settled++
+		case "voided":
+			run.Voided++
+		default:
+			run.Failed++
+		}
+		details = append(details, rec)
+	}
+
+	data, _ := json.Marshal(details)
+	run.Details = string(data)
+	run.FinishedAt = time.Now()
+
+	if err := s.db.Create(&run).Error; err != nil {
+		s.logger.Errorf("Failed to save recovery summary: %v", err)
+	}
+
+	s.logger.Infof("Recovery (%s): %d stale rounds, %d settled, %d voided, %d failed",
+		policy, len(rounds), run.Settled, run.Voided, run.Failed)
+	return &run
+}
+
+// settleStaleRound closes, draws and settles a stale round, voiding it if
+// the draw cannot be obtained. A pending round never took bets and is voided.
+func (s *Scheduler) settleStaleRound(round *model.PC28Round) (string, error) {
+	switch round.Status {
+	case model.RoundStatusPending:
+		return "voided", s.voidStaleRound(round, "Round was never opened")
+	case model.RoundStatusOpen:
+		if err := s.cycle.Transition(round, model.RoundStatusClosed, nil); err != nil {
+			return "", err
+		}
+	}
+
+	// Past the grace period the provider either has the draw or never will
+	if round.DrawnAt == nil && !s.drawRound(round, 0) {
+		return "voided", s.voidStaleRound(round, "Draw unavailable after server downtime")
+	}
+
+	if err := s.settleRound(round); err != nil {
+		return "", err
+	}
+	return "settled", nil
+}
+
+// voidStaleRound voids a round, refunds its bets and notifies the room
+func (s *Scheduler) voidStaleRound(round *model.PC28Round, reason string) error {
+	res, err := s.roundSvc.VoidRound(round.ID, reason)
+	if err != nil {
+		return err
+	}
+
+	if room, err := s.roomSvc.GetByID(round.RoomID); err == nil {
+		s.hub.BroadcastRoundVoid(websocket.RoomTopic(room.Code), map[string]interface{}{
+			"room":            room.Code,
+			"round_id":        res.Round.ID,
+			"issue_number":    res.Round.IssueNumber,
+			"reason":          reason,
+			"refunded_bets":   res.RefundedBets,
+			"refunded_amount": res.RefundedAmount,
+		})
+	}
+	return nil
+}
@@ -1,9 +1,12 @@
 package tasks
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
+	"pcgame/backend/internal/config"
 	"pcgame/backend/internal/model"
 	"pcgame/backend/internal/service"
 	"pcgame/backend/internal/websocket"
@@ -11,6 +14,7 @@ import (
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Scheduler handles scheduled tasks for the game
@@ -20,77 +24,273 @@ type Scheduler struct {
 	logger  *zap.SugaredLogger
 	cron    *cron.Cron
 	gameSvc *service.GameService
+	keno    service.KenoSource
+	kenoCfg config.KenoConfig
+	policy  string          // Recovery policy for stale rounds at startup
+	grace   time.Duration   // How long after close a round counts as stale
+	ahead   int             // Pending rounds kept scheduled per room
+	drawMu  sync.Mutex      // Prevents overlapping draw passes while a provider is slow
+	roundMu sync.Mutex      // Prevents overlapping lifecycle ticks
+	commMu  sync.Mutex      // Prevents overlapping commission settlements
+	invalid map[uint]string // Rooms left unscheduled for invalid settings, with the error last logged
+
+	settingsSvc *service.SettingsService
+	roomSvc     *service.RoomService
+	roundSvc    *service.RoundService
+	chaseSvc    *service.ChaseService
+	commSvc     *service.CommissionService
+	cycle       *service.RoundLifecycle
+
+	unsubscribe  func()         // Stops pushing round events
+	elector      *LeaderElector // nil when leader election is disabled
+	stopElection context.CancelFunc
+	electionDone chan struct{}
 }
 
 // NewScheduler creates a new scheduler
-func NewScheduler(db *gorm.DB, hub *websocket.Hub, logger *zap.SugaredLogger) *Scheduler {
-	return &Scheduler{
+func NewScheduler(db *gorm.DB, hub *websocket.Hub, logger *zap.SugaredLogger, cfg *config.Config) *Scheduler {
+	gameSvc := service.NewGameService()
+	ahead := cfg.Game.ScheduleAhead
+	if ahead < 1 {
+		ahead = 1
+	}
+	s := &Scheduler{
 		db:      db,
 		hub:     hub,
 		logger:  logger,
 		cron:    cron.New(cron.WithSeconds()),
-		gameSvc: service.NewGameService(),
+		gameSvc: gameSvc,
+		keno:    service.NewKenoSource(cfg, gameSvc),
+		kenoCfg: cfg.Keno,
+		policy:  cfg.Game.RecoveryPolicy,
+		grace:   time.Duration(cfg.Game.RecoveryGrace) * time.Second,
+		ahead:   ahead,
+		invalid: make(map[uint]string),
+
+		settingsSvc: service.NewSettingsService(db),
+		roomSvc:     service.NewRoomService(db),
+		roundSvc:    service.NewRoundService(db),
+		chaseSvc:    service.NewChaseService(db),
+		commSvc:     service.NewCommissionService(db, model.RateFromFloat(cfg.Referral.CommissionRate), cfg.Referral.AutoCredit),
+		cycle:       service.NewRoundLifecycle(db),
+	}
+
+	// Push round transitions made anywhere in this process to the room's subscribers
+	s.unsubscribe = service.SubscribeRoundEvents(s.broadcastRoundEvent)
+
+	if cfg.Scheduler.LeaderElection {
+		// A new leader first resolves rounds and commissions the previous one left behind
+		s.elector = NewLeaderElector(db, logger, cfg.Scheduler.LeaderLockKey,
+			time.Duration(cfg.Scheduler.LeaseInterval)*time.Second, func() {
+				s.Recover()
+				go s.settleCommissions()
+			})
 	}
+	return s
 }
 
 // Start starts the scheduler
+// With leader election enabled, ticks only do work on the instance holding the lock
 func (s *Scheduler) Start() {
-	// Run every minute at :00 seconds - create new round and settle previous
-	s.cron.AddFunc("0 * * * * *", s.processRounds)
+	if s.elector == nil {
+		// Resolve rounds left stale by downtime before the first tick
+		s.Recover()
+		go s.settleCommissions()
+	} else {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.stopElection = cancel
+		s.electionDone = make(chan struct{})
+		go func() {
+			defer close(s.electionDone)
+			s.elector.Run(ctx)
+		}()
+	}
+
+	// Check the round lifecycle every second; cadence comes from game settings
+	s.cron.AddFunc("* * * * * *", s.processRounds)
 
 	// Countdown every second
 	s.cron.AddFunc("* * * * * *", s.broadcastCountdown)
 
+	// Referral commissions for the previous day, once its last rounds have settled
+	s.cron.AddFunc("0 10 0 * * *", s.settleCommissions)
+
 	s.cron.Start()
-	s.logger.Info("Scheduler started")
+	s.logger.Infof("Scheduler started (keno source: %s)", s.keno.Name())
 }
 
 // Stop stops the scheduler
 func (s *Scheduler) Stop() {
-	s.cron.Stop()
+	<-s.cron.Stop().Done()
+	if s.stopElection != nil {
+		s.stopElection()
+		<-s.electionDone
+	}
+	s.unsubscribe()
 	s.logger.Info("Scheduler stopped")
 }
 
+// isLeader reports whether this instance should run the scheduled work
+func (s *Scheduler) isLeader() bool {
+	return s.elector == nil || s.elector.IsLeader()
+}
+
 // processRounds handles round lifecycle
 func (s *Scheduler) processRounds() {
-	now := time.Now()
+	if !s.isLeader() || !s.roundMu.TryLock() {
+		return
+	}
+	defer s.roundMu.Unlock()
 
-	// 1. Settle any closed rounds that haven't been settled
+	now := time.Now().Truncate(time.Second)
+
+	// 1. Settle any closed rounds that have been drawn
 	s.settleClosedRounds()
 
-	// 2. Close the current open round
+	// 2. Close open rounds past their close time
 	s.closeOpenRounds()
 
-	// 3. Create a new round
-	s.createNewRound(now)
+	// 3. In each room, keep the upcoming rounds scheduled
+	rooms, err := s.roomSvc.ListActive()
+	if err != nil {
+		s.logger.Errorf("Failed to load game rooms: %v", err)
+		return
+	}
+	for i := range rooms {
+		room := &rooms[i]
+		settings, err := s.settingsSvc.GetForRoom(room)
+		if err != nil {
+			s.logger.Errorf("Failed to load game settings for room %s: %v", room.Code, err)
+			continue
+		}
+		// Settings saved before a rule applied (e.g. the provider's draw interval) are not used
+		if err := settings.Validate(); err != nil {
+			if s.invalid[room.ID] != err.Error() {
+				s.invalid[room.ID] = err.Error()
+				s.logger.Errorf("Not scheduling room %s until its settings are fixed: %v", room.Code, err)
+			}
+			continue
+		}
+		delete(s.invalid, room.ID)
+		s.scheduleRounds(now, room, settings)
+	}
+
+	// 4. Open pending rounds whose open time has come
+	s.openDueRounds(now)
+
+	// 5. Fetch draws for closed rounds (may wait on the provider)
+	go s.drawClosedRounds()
+}
+
+// scheduleRounds keeps the room's next rounds created ahead of time as pending
+//
+// Rounds sit on a fixed daily grid of round_duration slots from local midnight;
+// the issue number comes from the slot's open time (see generateIssueNumber).
+// A slot is skipped if its betting window has passed or it would overlap the
+// room's last round (e.g. after the cadence was changed).
+func (s *Scheduler) scheduleRounds(now time.Time, room *model.GameRoom, settings *service.GameSettings) {
+	var pending int64
+	s.db.Model(&model.PC28Round{}).
+		Where("room_id = ? AND status = ?", room.ID, model.RoundStatusPending).
+		Count(&pending)
+	if int(pending) >= s.ahead {
+		return
+	}
+
+	duration := time.Duration(settings.RoundDuration) * time.Second
+	window := time.Duration(settings.BettingWindow) * time.Second
+
+	start := slotStart(now, settings.RoundDuration)
+	var last model.PC28Round
+	if err := s.db.Where("room_id = ?", room.ID).Order("open_time desc").First(&last).Error; err == nil {
+		for start.Before(last.CloseTime) {
+			start = start.Add(duration)
+		}
+	}
+
+	for created := int(pending); created < s.ahead; start = start.Add(duration) {
+		if !start.Add(window).After(now) {
+			continue // too late to take bets
+		}
+		if s.createRound(room, start, start.Add(window)) {
+			created++
+		} else {
+			return // retry on the next tick
+		}
+	}
 }
 
-// createNewRound creates a new betting round
-func (s *Scheduler) createNewRound(now time.Time) {
-	issueNumber := now.Format("20060102150405")
+// createRound creates a pending round for a slot and reports whether it exists afterwards
+func (s *Scheduler) createRound(room *model.GameRoom, openTime, closeTime time.Time) bool {
+	issueNumber := room.IssuePrefix + generateIssueNumber(openTime)
 
 	round := model.PC28Round{
+		RoomID:      room.ID,
 		IssueNumber: issueNumber,
-		OpenTime:    now,
-		CloseTime:   now.Add(55 * time.Second), // 55 seconds betting window
-		Status:      model.RoundStatusOpen,
+		OpenTime:    openTime,
+		CloseTime:   closeTime,
+		Status:      model.RoundStatusPending,
 	}
 
-	if err := s.db.Create(&round).Error; err != nil {
-		s.logger.Errorf("Failed to create round: %v", err)
-		return
+	// Commit to the draw up front when the source can derive it from a seed
+	if _, ok := s.keno.(service.SeededKenoSource); ok {
+		round.ServerSeed = s.gameSvc.GenerateServerSeed()
+		round.ServerSeedHash = s.gameSvc.HashServerSeed(round.ServerSeed)
+	}
+	if mapped, ok := s.keno.(service.IssueMappedKenoSource); ok {
+		round.DrawIssue = mapped.ProviderIssue(closeTime)
 	}
 
-	s.logger.Infof("Created new round: %s", issueNumber)
+	res := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&round)
+	if res.Error != nil {
+		s.logger.Errorf("Failed to create round %s: %v", issueNumber, res.Error)
+		return false
+	}
+	if res.RowsAffected > 0 {
+		s.logger.Infof("Scheduled round %s (room %s) at %s", issueNumber, room.Code, openTime.Format(time.TimeOnly))
+		return true
+	}
 
-	// Broadcast new round
-	s.hub.BroadcastRoundUpdate(map[string]interface{}{
-		"round_id":     round.ID,
-		"issue_number": round.IssueNumber,
-		"open_time":    round.OpenTime,
-		"close_time":   round.CloseTime,
-		"status":       round.Status,
-	})
+	// The issue number is taken: fine if it is this very slot, created by an
+	// earlier tick, but another round must not be mistaken for it
+	var existing model.PC28Round
+	if err := s.db.Unscoped().Where("issue_number = ?", issueNumber).First(&existing).Error; err != nil {
+		s.logger.Errorf("Failed to load round %s after a conflict: %v", issueNumber, err)
+		return false
+	}
+	if existing.RoomID != room.ID || !existing.OpenTime.Equal(openTime) {
+		s.logger.Errorf("Cannot schedule room %s at %s: issue number %s belongs to room %d at %s",
+			room.Code, openTime.Format(time.DateTime), issueNumber, existing.RoomID, existing.OpenTime.Format(time.DateTime))
+		return false
+	}
+	return true
+}
+
+// openDueRounds opens pending rounds whose open time has come; a pending round
+// already past its close time never took bets and is voided instead
+func (s *Scheduler) openDueRounds(now time.Time) {
+	var rounds []model.PC28Round
+	s.db.Where("status = ? AND open_time <= ?", model.RoundStatusPending, now).
+		Order("id asc").
+		Find(&rounds)
+
+	for i := range rounds {
+		round := &rounds[i]
+		if !round.CloseTime.After(now) {
+			if _, err := s.roundSvc.VoidRound(round.ID, "Round was never opened"); err != nil {
+				s.logger.Errorf("Failed to void unopened round %s: %v", round.IssueNumber, err)
+			}
+			continue
+		}
+
+		if err := s.cycle.Transition(round, model.RoundStatusOpen, nil); err != nil {
+			s.logger.Errorf("Failed to open round %s: %v", round.IssueNumber, err)
+			continue
+		}
+
+		s.logger.Infof("Opened round %s", round.IssueNumber)
+		s.placeChaseBets(round)
+	}
 }
 
 // closeOpenRounds closes any open rounds that have passed their close time
@@ -100,120 +300,215 @@ func (s *Scheduler) closeOpenRounds() {
 	var rounds []model.PC28Round
 	s.db.Where("status = ? AND close_time <= ?", model.RoundStatusOpen, now).Find(&rounds)
 
-	for _, round := range rounds {
-		// Generate mock Keno data
-		kenoData := s.gameSvc.GenerateMockKenoData()
-		result := s.gameSvc.CalculateResult(kenoData)
-
-		round.KenoData = s.gameSvc.KenoDataToJSON(kenoData)
-		round.ResultA = result.A
-		round.ResultB = result.B
-		round.ResultC = result.C
-		round.Sum = result.Sum
-		round.Status = model.RoundStatusClosed
-
-		if err := s.db.Save(&round).Error; err != nil {
+	for i := range rounds {
+		round := &rounds[i]
+		if err := s.cycle.Transition(round, model.RoundStatusClosed, nil); err != nil {
 			s.logger.Errorf("Failed to close round %s: %v", round.IssueNumber, err)
 			continue
 		}
 
-		s.logger.Infof("Closed round %s with result: %d", round.IssueNumber, result.Sum)
+		s.logger.Infof("Closed round %s", round.IssueNumber)
+	}
+}
+
+// drawClosedRounds fetches the Keno draw for closed rounds that have none yet
+func (s *Scheduler) drawClosedRounds() {
+	if !s.drawMu.TryLock() {
+		return // previous pass is still waiting on the provider
+	}
+	defer s.drawMu.Unlock()
+
+	var rounds []model.PC28Round
+	s.db.Where("status = ? AND drawn_at IS NULL", model.RoundStatusClosed).
+		Order("id asc").
+		Find(&rounds)
+
+	for i := range rounds {
+		s.drawRound(&rounds[i], s.kenoCfg.MaxRetries)
+	}
+}
+
+// drawIssue is the issue to request from the source: the provider's own
+// number when it has one, which rounds created before the mapping lack
+func (s *Scheduler) drawIssue(round *model.PC28Round) string {
+	if round.DrawIssue != "" {
+		return round.DrawIssue
+	}
+	if mapped, ok := s.keno.(service.IssueMappedKenoSource); ok {
+		return mapped.ProviderIssue(round.CloseTime)
+	}
+	return round.IssueNumber
+}
 
-		// Broadcast result
-		s.hub.BroadcastResult(map[string]interface{}{
-			"round_id":     round.ID,
-			"issue_number": round.IssueNumber,
-			"keno_data":    kenoData,
-			"result_a":     result.A,
-			"result_b":     result.B,
-			"result_c":     result.C,
-			"sum":          result.Sum,
-		})
+// fetchDraw returns the round's Keno numbers. A round that committed to a
+// server seed is drawn from it whatever source is configured now, so the
+// published hash and /games/pc28/verify keep matching its result.
+func (s *Scheduler) fetchDraw(round *model.PC28Round, retries int) ([]int, error) {
+	if round.ServerSeed != "" {
+		return s.gameSvc.GenerateSeededKenoData(round.ServerSeed, round.IssueNumber), nil
 	}
+
+	interval := time.Duration(s.kenoCfg.RetryInterval) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Second)
+	defer cancel()
+	return service.FetchDrawWithRetry(ctx, s.keno, s.drawIssue(round), retries, interval)
+}
+
+// drawRound fetches the draw for a round, retrying up to retries times while
+// the provider is not ready. It reports whether the round took the draw.
+func (s *Scheduler) drawRound(round *model.PC28Round, retries int) bool {
+	room, game, err := s.roomSvc.GameForRound(round)
+	if err != nil {
+		s.logger.Errorf("Failed to load game for round %s: %v", round.IssueNumber, err)
+		return false
+	}
+
+	kenoData, err := s.fetchDraw(round, retries)
+	if err != nil {
+		// Leave the round closed without a draw; the next tick retries
+		s.logger.Warnf("Draw %s for round %s not available from %s: %v", s.drawIssue(round), round.IssueNumber, s.keno.Name(), err)
+		return false
+	}
+
+	result := game.Result(kenoData)
+	drawnAt := time.Now()
+
+	round.KenoData = s.gameSvc.KenoDataToJSON(kenoData)
+	round.ResultA = result.A
+	round.ResultB = result.B
+	round.ResultC = result.C
+	round.Sum = result.Sum
+	round.DrawnAt = &drawnAt
+
+	// Only a round that is still closed takes the draw; it may have been voided meanwhile
+	update := s.db.Model(round).Where("status = ?", model.RoundStatusClosed).Updates(map[string]interface{}{
+		"keno_data": round.KenoData,
+		"result_a":  result.A,
+		"result_b":  result.B,
+		"result_c":  result.C,
+		"sum":       result.Sum,
+		"drawn_at":  drawnAt,
+	})
+	if update.Error != nil {
+		s.logger.Errorf("Failed to save draw for round %s: %v", round.IssueNumber, update.Error)
+		return false
+	}
+	if update.RowsAffected == 0 {
+		s.logger.Warnf("Round %s is no longer closed, discarding draw", round.IssueNumber)
+		return false
+	}
+
+	s.logger.Infof("Drew round %s with result: %d", round.IssueNumber, result.Sum)
+
+	// Broadcast result
+	s.hub.BroadcastResult(websocket.RoomTopic(room.Code), map[string]interface{}{
+		"room":             room.Code,
+		"game":             game.Code(),
+		"round_id":         round.ID,
+		"issue_number":     round.IssueNumber,
+		"keno_data":        kenoData,
+		"result_a":         result.A,
+		"result_b":         result.B,
+		"result_c":         result.C,
+		"sum":              result.Sum,
+		"server_seed_hash": round.ServerSeedHash,
+		"server_seed":      round.ServerSeed,
+	})
+
+	return true
 }
 
 // settleClosedRounds settles bets for closed rounds
 func (s *Scheduler) settleClosedRounds() {
 	var rounds []model.PC28Round
-	s.db.Where("status = ?", model.RoundStatusClosed).Find(&rounds)
+	s.db.Where("status = ? AND drawn_at IS NOT NULL", model.RoundStatusClosed).Find(&rounds)
 
-	for _, round := range rounds {
-		s.settleRound(&round)
+	for i := range rounds {
+		s.settleRound(&rounds[i])
 	}
 }
 
 // settleRound settles all bets for a specific round
-func (s *Scheduler) settleRound(round *model.PC28Round) {
-	result := service.PC28Result{
-		A:   round.ResultA,
-		B:   round.ResultB,
-		C:   round.ResultC,
-		Sum: round.Sum,
-	}
-
-	var bets []model.PC28Bet
-	s.db.Where("round_id = ? AND status = ?", round.ID, model.BetStatusPending).Find(&bets)
-
-	for _, bet := range bets {
-		// Start transaction
-		tx := s.db.Begin()
-
-		won := s.gameSvc.CheckWin(string(bet.BetType), bet.BetValue, result)
-
-		if won {
-			winAmount := bet.Amount * bet.Odds
-			bet.WinAmount = winAmount
-			bet.Status = model.BetStatusWon
-
-			// Update user balance
-			if err := tx.Model(&model.User{}).Where("id = ?", bet.UserID).
-				Update("balance", gorm.Expr("balance + ?", winAmount)).Error; err != nil {
-				tx.Rollback()
-				s.logger.Errorf("Failed to update user balance: %v", err)
-				continue
-			}
-		} else {
-			bet.Status = model.BetStatusLost
-		}
+// Settlement is idempotent, so a retry after a failure is always safe
+func (s *Scheduler) settleRound(round *model.PC28Round) error {
+	res, err := s.roundSvc.SettleRound(round.ID)
+	if err != nil {
+		s.logger.Errorf("Failed to settle round %s: %v", round.IssueNumber, err)
+		return err
+	}
+	*round = res.Round
 
-		if err := tx.Save(&bet).Error; err != nil {
-			tx.Rollback()
-			s.logger.Errorf("Failed to update bet: %v", err)
-			continue
-		}
+	s.logger.Infof("Settled round %s: %d won, %d lost, %s paid to %d players",
+		round.IssueNumber, res.WonBets, res.LostBets, res.Payout, len(res.UserIDs))
 
-		tx.Commit()
+	if n, err := s.chaseSvc.StopWonPlans(round); err != nil {
+		s.logger.Errorf("Failed to stop won chase plans of round %s: %v", round.IssueNumber, err)
+	} else if n > 0 {
+		s.logger.Infof("Stopped %d chase plans that won in round %s", n, round.IssueNumber)
 	}
+	return nil
+}
 
-	// Mark round as settled
-	round.Status = model.RoundStatusSettled
-	s.db.Save(round)
-
-	s.logger.Infof("Settled round %s", round.IssueNumber)
+// placeChaseBets places this round's bet of every active chase plan in its room
+func (s *Scheduler) placeChaseBets(round *model.PC28Round) {
+	n, err := s.chaseSvc.PlaceForRound(round)
+	if err != nil {
+		s.logger.Errorf("Failed to place chase bets for round %s: %v", round.IssueNumber, err)
+	}
+	if n > 0 {
+		s.logger.Infof("Placed %d chase bets for round %s", n, round.IssueNumber)
+	}
 }
 
-// broadcastCountdown broadcasts the current countdown
+// broadcastCountdown broadcasts the current countdown of each room
 func (s *Scheduler) broadcastCountdown() {
-	var round model.PC28Round
-	if err := s.db.Where("status = ?", model.RoundStatusOpen).Order("id desc").First(&round).Error; err != nil {
+	if !s.isLeader() {
+		return
+	}
+
+	rooms, err := s.roomSvc.ListActive()
+	if err != nil {
 		return
 	}
 
-	remaining := int(time.Until(round.CloseTime).Seconds())
-	if remaining < 0 {
-		remaining = 0
+	for _, room := range rooms {
+		var round model.PC28Round
+		if err := s.db.Where("room_id = ? AND status = ?", room.ID, model.RoundStatusOpen).
+			Order("id desc").First(&round).Error; err != nil {
+			continue
+		}
+
+		remaining := int(time.Until(round.CloseTime).Seconds())
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		s.hub.BroadcastCountdown(websocket.RoomTopic(room.Code), room.Code, remaining)
 	}
+}
 
-	s.hub.BroadcastCountdown(remaining)
+// slotStart returns the start of the grid slot containing t; slots of
+// duration seconds are counted from local midnight
+func slotStart(t time.Time, duration int) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	elapsed := int(t.Sub(midnight) / time.Second)
+	return midnight.Add(time.Duration(elapsed/duration*duration) * time.Second)
 }
 
-// generateIssueNumber generates a unique issue number based on time
+// issueTick is the time step of the daily issue sequence; no round is shorter
+const issueTick = 10 * time.Second
+
+// generateIssueNumber generates the issue number of the round opening at t
 func generateIssueNumber(t time.Time) string {
-	return fmt.Sprintf("%s%03d", t.Format("20060102"), getDailySequence(t))
+	return fmt.Sprintf("%s%04d", t.Format("20060102"), getDailySequence(t))
 }
 
-// getDailySequence returns the sequence number for the day
+// getDailySequence returns the 1-based number of the 10-second tick at which
+// t falls within its day. It depends only on the open time, not the cadence,
+// so rounds of one room never share it even after round_duration changes.
+// Time is measured since midnight rather than read from the clock, so an
+// hour repeated by a DST change gets new numbers (at most 9000 per day).
 func getDailySequence(t time.Time) int {
-	// Each round is 1 minute, so max 1440 rounds per day
-	return t.Hour()*60 + t.Minute()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return int(t.Sub(midnight)/issueTick) + 1
 }
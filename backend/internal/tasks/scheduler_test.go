@@ -0,0 +1,107 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"pcgame/backend/internal/model"
+	"pcgame/backend/internal/service"
+)
+
+func TestGenerateIssueNumber(t *testing.T) {
+	day := time.Date(2026, 1, 22, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		at   time.Duration
+		want string
+	}{
+		{0, "202601220001"},
+		{10 * time.Second, "202601220002"},
+		{12*time.Hour + time.Minute, "202601224327"},
+		{24*time.Hour - 10*time.Second, "202601228640"},
+	}
+	for _, tt := range tests {
+		if got := generateIssueNumber(day.Add(tt.at)); got != tt.want {
+			t.Errorf("generateIssueNumber(%s) = %s, want %s", day.Add(tt.at).Format(time.TimeOnly), got, tt.want)
+		}
+	}
+}
+
+func TestIssueNumbersSurviveCadenceChange(t *testing.T) {
+	// 60s rounds until noon, then 180s rounds: every slot keeps its own number
+	day := time.Date(2026, 1, 22, 0, 0, 0, 0, time.UTC)
+	seen := make(map[string]time.Time)
+	add := func(open time.Time) {
+		issue := generateIssueNumber(open)
+		if prev, ok := seen[issue]; ok {
+			t.Fatalf("issue %s used by %s and %s", issue, prev.Format(time.TimeOnly), open.Format(time.TimeOnly))
+		}
+		seen[issue] = open
+	}
+
+	noon := day.Add(12 * time.Hour)
+	for open := day; open.Before(noon); open = open.Add(time.Minute) {
+		add(open)
+	}
+	for open := slotStart(noon, 180); open.Before(day.AddDate(0, 0, 1)); open = open.Add(3 * time.Minute) {
+		if !open.Before(noon) {
+			add(open)
+		}
+	}
+}
+
+func TestIssueNumbersAcrossDSTRepeatedHour(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	// Clocks fall back from 02:00 to 01:00 on 2026-11-01
+	first := time.Date(2026, 11, 1, 1, 30, 0, 0, loc) // resolves to the first 01:30
+	second := first.Add(time.Hour)
+	if first.Format(time.TimeOnly) != second.Format(time.TimeOnly) {
+		t.Fatalf("expected the same wall clock, got %s and %s", first, second)
+	}
+	if a, b := generateIssueNumber(first), generateIssueNumber(second); a == b {
+		t.Errorf("repeated hour reused issue number %s", a)
+	}
+}
+
+// providerOnly is an unseeded source that must not be asked for committed rounds
+type providerOnly struct{ calls int }
+
+func (p *providerOnly) FetchDraw(ctx context.Context, issue string) ([]int, error) {
+	p.calls++
+	return nil, errors.New("provider should not be asked")
+}
+
+func (p *providerOnly) Name() string { return "provider" }
+
+func TestCommittedRoundDrawnFromSeedAfterSourceChange(t *testing.T) {
+	gameSvc := service.NewGameService()
+	src := &providerOnly{}
+	s := &Scheduler{gameSvc: gameSvc, keno: src}
+
+	seed := gameSvc.GenerateServerSeed()
+	round := &model.PC28Round{IssueNumber: "202601224327", ServerSeed: seed}
+
+	got, err := s.fetchDraw(round, 0)
+	if err != nil {
+		t.Fatalf("fetchDraw() error = %v", err)
+	}
+	if want := gameSvc.GenerateSeededKenoData(seed, round.IssueNumber); !reflect.DeepEqual(got, want) {
+		t.Errorf("fetchDraw() = %v, want the seeded draw %v", got, want)
+	}
+	if src.calls != 0 {
+		t.Errorf("provider asked %d times for a committed round", src.calls)
+	}
+
+	// Without a commitment the configured source decides
+	round.ServerSeed = ""
+	if _, err := s.fetchDraw(round, 0); err == nil || src.calls != 1 {
+		t.Errorf("fetchDraw() without seed: err = %v after %d calls, want the provider's error after 1", err, src.calls)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"sync"
 
+	"pcgame/backend/internal/model"
+
 	"github.com/gorilla/websocket"
 )
 
@@ -13,8 +15,18 @@ const (
 	MsgTypeCountdown    = "countdown"
 	MsgTypeResult       = "result"
 	MsgTypeBetConfirmed = "bet_confirmed"
+	MsgTypeRoundVoid    = "round_void"
+	MsgTypeBalance      = "balance_update" // Sent only to the player whose balance changed
+	MsgTypeFunding      = "funding_update" // Sent only to the player whose deposit or withdrawal changed
+	MsgTypeSubscribe    = "subscribe"      // client -> server: {"type":"subscribe","payload":{"room":"pc28_3m"}}
+	MsgTypeUnsubscribe  = "unsubscribe"    // client -> server
 )
 
+// RoomTopic is the topic carrying a room's round events
+func RoomTopic(roomCode string) string {
+	return "room:" + roomCode
+}
+
 // Message represents a WebSocket message
 type Message struct {
 	Type    string      `json:"type"`
@@ -27,12 +39,22 @@ type Client struct {
 	conn   *websocket.Conn
 	send   chan []byte
 	userID uint
+	topics map[string]bool
+	mu     sync.RWMutex
+}
+
+// envelope is a serialized message addressed to a topic ("" = all clients)
+// or, when userID is set, to that player's connections only
+type envelope struct {
+	topic  string
+	userID uint
+	data   []byte
 }
 
 // Hub maintains the set of active clients and broadcasts messages
 type Hub struct {
 	clients    map[*Client]bool
-	broadcast  chan []byte
+	broadcast  chan envelope
 	register   chan *Client
 	unregister chan *Client
 	mu         sync.RWMutex
@@ -42,7 +64,7 @@ type Hub struct {
 func NewHub() *Hub {
 	return &Hub{
 		clients:    make(map[*Client]bool),
-		broadcast:  make(chan []byte, 256),
+		broadcast:  make(chan envelope, 256),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 	}
@@ -66,60 +88,119 @@ func (h *Hub) Run() {
 			h.mu.Unlock()
 
 		case message := <-h.broadcast:
-			h.mu.RLock()
+			h.mu.Lock()
 			for client := range h.clients {
+				if message.userID != 0 && client.userID != message.userID {
+					continue
+				}
+				if message.topic != "" && !client.Subscribed(message.topic) {
+					continue
+				}
 				select {
-				case client.send <- message:
+				case client.send <- message.data:
 				default:
 					close(client.send)
 					delete(h.clients, client)
 				}
 			}
-			h.mu.RUnlock()
+			h.mu.Unlock()
 		}
 	}
 }
 
 // Broadcast sends a message to all connected clients
 func (h *Hub) Broadcast(msg Message) {
+	h.BroadcastTo("", msg)
+}
+
+// BroadcastTo sends a message to clients subscribed to a topic
+func (h *Hub) BroadcastTo(topic string, msg Message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	h.broadcast <- envelope{topic: topic, data: data}
+}
+
+// SendToUser sends a message to every connection of one player
+func (h *Hub) SendToUser(userID uint, msg Message) {
 	data, err := json.Marshal(msg)
 	if err != nil {
 		return
 	}
-	h.broadcast <- data
+	h.broadcast <- envelope{userID: userID, data: data}
 }
 
-// BroadcastRoundUpdate sends round update to all clients
-func (h *Hub) BroadcastRoundUpdate(roundData interface{}) {
-	h.Broadcast(Message{
+// SendBalance pushes a player's new balance and what changed it
+func (h *Hub) SendBalance(userID uint, balance model.Money, reason string) {
+	h.SendToUser(userID, Message{
+		Type:    MsgTypeBalance,
+		Payload: map[string]interface{}{"balance": balance, "reason": reason},
+	})
+}
+
+// BroadcastRoundUpdate sends round update to the room's subscribers
+func (h *Hub) BroadcastRoundUpdate(topic string, roundData interface{}) {
+	h.BroadcastTo(topic, Message{
 		Type:    MsgTypeRoundUpdate,
 		Payload: roundData,
 	})
 }
 
-// BroadcastCountdown sends countdown update to all clients
-func (h *Hub) BroadcastCountdown(seconds int) {
-	h.Broadcast(Message{
+// BroadcastCountdown sends countdown update to the room's subscribers
+func (h *Hub) BroadcastCountdown(topic string, roomCode string, seconds int) {
+	h.BroadcastTo(topic, Message{
 		Type:    MsgTypeCountdown,
-		Payload: map[string]int{"seconds": seconds},
+		Payload: map[string]interface{}{"seconds": seconds, "room": roomCode},
 	})
 }
 
-// BroadcastResult sends result to all clients
-func (h *Hub) BroadcastResult(result interface{}) {
-	h.Broadcast(Message{
+// BroadcastResult sends result to the room's subscribers
+func (h *Hub) BroadcastResult(topic string, result interface{}) {
+	h.BroadcastTo(topic, Message{
 		Type:    MsgTypeResult,
 		Payload: result,
 	})
 }
 
-// NewClient creates a new client
-func NewClient(hub *Hub, conn *websocket.Conn, userID uint) *Client {
-	return &Client{
+// BroadcastRoundVoid notifies the room's subscribers that a round was voided and refunded
+func (h *Hub) BroadcastRoundVoid(topic string, data interface{}) {
+	h.BroadcastTo(topic, Message{
+		Type:    MsgTypeRoundVoid,
+		Payload: data,
+	})
+}
+
+// NewClient creates a new client subscribed to the given topics
+func NewClient(hub *Hub, conn *websocket.Conn, userID uint, topics ...string) *Client {
+	c := &Client{
 		hub:    hub,
 		conn:   conn,
 		send:   make(chan []byte, 256),
 		userID: userID,
+		topics: make(map[string]bool),
+	}
+	for _, t := range topics {
+		c.topics[t] = true
+	}
+	return c
+}
+
+// Subscribed reports whether the client listens to a topic
+func (c *Client) Subscribed(topic string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.topics[topic]
+}
+
+// setSubscription adds or removes a topic
+func (c *Client) setSubscription(topic string, on bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if on {
+		c.topics[topic] = true
+	} else {
+		delete(c.topics, topic)
 	}
 }
 
@@ -154,10 +235,25 @@ func (c *Client) ReadPump() {
 	}()
 
 	for {
-		_, _, err := c.conn.ReadMessage()
+		_, data, err := c.conn.ReadMessage()
 		if err != nil {
 			break
 		}
-		// Handle incoming messages if needed
+
+		var msg struct {
+			Type    string `json:"type"`
+			Payload struct {
+				Room string `json:"room"`
+			} `json:"payload"`
+		}
+		if json.Unmarshal(data, &msg) != nil || msg.Payload.Room == "" {
+			continue
+		}
+		switch msg.Type {
+		case MsgTypeSubscribe:
+			c.setSubscription(RoomTopic(msg.Payload.Room), true)
+		case MsgTypeUnsubscribe:
+			c.setSubscription(RoomTopic(msg.Payload.Room), false)
+		}
 	}
 }
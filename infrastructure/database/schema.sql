@@ -50,7 +50,7 @@ CREATE TABLE IF NOT EXISTS users (
     deleted_at TIMESTAMP WITH TIME ZONE,
     username VARCHAR(50) UNIQUE NOT NULL,
     password VARCHAR(255) NOT NULL,
-    balance DECIMAL(15, 2) DEFAULT 0.00,
+    balance BIGINT DEFAULT 0,  -- 金额一律以分为单位存储
     role VARCHAR(20) DEFAULT 'user',
     operator_id INTEGER REFERENCES operators(id),
     referrer_id INTEGER REFERENCES users(id),
@@ -63,6 +63,29 @@ CREATE INDEX idx_users_operator_id ON users(operator_id);
 CREATE INDEX idx_users_referrer_id ON users(referrer_id);
 CREATE INDEX idx_users_invite_code ON users(invite_code);
 
+-- ========================================
+-- Game Rooms (游戏房间, 各自独立的轮次节奏/期号前缀/赔率)
+-- ========================================
+
+CREATE TABLE IF NOT EXISTS game_rooms (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    code VARCHAR(20) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    game_code VARCHAR(20) DEFAULT 'pc28',  -- 玩法 (service.RegisterGame 注册)
+    issue_prefix VARCHAR(10),
+    round_duration INTEGER DEFAULT 0,  -- 0 = 使用全局设置
+    betting_window INTEGER DEFAULT 0,  -- 0 = 使用全局设置
+    odds JSONB DEFAULT '{}',           -- 覆盖全局赔率
+    number_odds JSONB DEFAULT '{}',
+    status VARCHAR(20) DEFAULT 'active',
+    sort_order INTEGER DEFAULT 0
+);
+
+CREATE INDEX idx_game_rooms_deleted_at ON game_rooms(deleted_at);
+
 -- ========================================
 -- PC28 Rounds (轮次)
 -- ========================================
@@ -72,20 +95,28 @@ CREATE TABLE IF NOT EXISTS pc28_rounds (
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     deleted_at TIMESTAMP WITH TIME ZONE,
+    room_id INTEGER NOT NULL REFERENCES game_rooms(id),
     issue_number VARCHAR(50) UNIQUE NOT NULL,
-    keno_data JSONB,
+    draw_issue VARCHAR(50),        -- Keno 数据源期号, 截止后的第一期
+    keno_data JSONB DEFAULT '[]',
     result_a INTEGER DEFAULT 0,
     result_b INTEGER DEFAULT 0,
     result_c INTEGER DEFAULT 0,
     sum INTEGER DEFAULT 0,
     open_time TIMESTAMP WITH TIME ZONE NOT NULL,
     close_time TIMESTAMP WITH TIME ZONE NOT NULL,
-    status VARCHAR(20) DEFAULT 'pending'
+    drawn_at TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(20) DEFAULT 'pending',
+    version INTEGER NOT NULL DEFAULT 0,  -- 乐观锁, 每次状态变更 +1
+    void_reason VARCHAR(255),
+    server_seed_hash VARCHAR(64),  -- SHA256(server_seed), published at open
+    server_seed VARCHAR(64)        -- revealed after the draw
 );
 
 CREATE INDEX idx_pc28_rounds_deleted_at ON pc28_rounds(deleted_at);
 CREATE INDEX idx_pc28_rounds_issue_number ON pc28_rounds(issue_number);
 CREATE INDEX idx_pc28_rounds_status ON pc28_rounds(status);
+CREATE INDEX idx_pc28_rounds_room_id ON pc28_rounds(room_id);
 
 -- ========================================
 -- PC28 Bets (投注)
@@ -100,16 +131,232 @@ CREATE TABLE IF NOT EXISTS pc28_bets (
     round_id INTEGER NOT NULL REFERENCES pc28_rounds(id),
     bet_type VARCHAR(20) NOT NULL,
     bet_value INTEGER DEFAULT 0,
-    amount DECIMAL(15, 2) NOT NULL,
-    odds DECIMAL(5, 2) NOT NULL,
+    amount BIGINT NOT NULL,                 -- 分
+    odds BIGINT NOT NULL,                   -- 万分之一, 1.95 存为 19500
     status VARCHAR(20) DEFAULT 'pending',
-    win_amount DECIMAL(15, 2) DEFAULT 0.00
+    win_amount BIGINT DEFAULT 0,            -- 分, amount * odds / 10000 向下取整
+    chase_plan_id INTEGER,
+    cancelled_at TIMESTAMP WITH TIME ZONE
 );
 
 CREATE INDEX idx_pc28_bets_deleted_at ON pc28_bets(deleted_at);
 CREATE INDEX idx_pc28_bets_user_id ON pc28_bets(user_id);
 CREATE INDEX idx_pc28_bets_round_id ON pc28_bets(round_id);
 CREATE INDEX idx_pc28_bets_status ON pc28_bets(status);
+CREATE INDEX idx_pc28_bets_chase_plan_id ON pc28_bets(chase_plan_id);
+CREATE INDEX idx_pc28_bets_cancelled_at ON pc28_bets(cancelled_at);
+
+-- ========================================
+-- Chase Plans (追号计划)
+-- ========================================
+
+CREATE TABLE IF NOT EXISTS chase_plans (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    room_id INTEGER NOT NULL REFERENCES game_rooms(id),
+    bet_type VARCHAR(20) NOT NULL,
+    bet_value INTEGER DEFAULT 0,
+    base_amount BIGINT NOT NULL,
+    multiplier DECIMAL(5, 2) NOT NULL DEFAULT 1,
+    total_rounds INTEGER NOT NULL,
+    placed_rounds INTEGER DEFAULT 0,
+    stop_on_win BOOLEAN DEFAULT FALSE,
+    reserved BIGINT NOT NULL,
+    spent BIGINT DEFAULT 0,
+    refunded BIGINT DEFAULT 0,
+    status VARCHAR(20) DEFAULT 'active',
+    stop_reason VARCHAR(255),
+    last_round_id INTEGER DEFAULT 0
+);
+
+CREATE INDEX idx_chase_plans_deleted_at ON chase_plans(deleted_at);
+CREATE INDEX idx_chase_plans_user_id ON chase_plans(user_id);
+CREATE INDEX idx_chase_plans_room_id ON chase_plans(room_id);
+CREATE INDEX idx_chase_plans_status ON chase_plans(status);
+
+ALTER TABLE pc28_bets ADD CONSTRAINT fk_pc28_bets_chase_plan
+    FOREIGN KEY (chase_plan_id) REFERENCES chase_plans(id);
+
+-- ========================================
+-- Wallet Transactions (账变记录)
+-- ========================================
+
+CREATE TABLE IF NOT EXISTS wallet_transactions (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    type VARCHAR(20) NOT NULL,
+    amount BIGINT NOT NULL,
+    balance_after BIGINT NOT NULL,
+    bet_id INTEGER REFERENCES pc28_bets(id),
+    round_id INTEGER REFERENCES pc28_rounds(id),
+    ref_type VARCHAR(20),
+    ref_id INTEGER,
+    note VARCHAR(255)
+);
+
+CREATE INDEX idx_wallet_transactions_deleted_at ON wallet_transactions(deleted_at);
+CREATE INDEX idx_wallet_transactions_user_id ON wallet_transactions(user_id);
+CREATE INDEX idx_wallet_transactions_type ON wallet_transactions(type);
+CREATE INDEX idx_wallet_transactions_bet_id ON wallet_transactions(bet_id);
+CREATE INDEX idx_wallet_transactions_round_id ON wallet_transactions(round_id);
+
+-- ========================================
+-- Funding Requests (充值/提现申请)
+-- ========================================
+
+CREATE TABLE IF NOT EXISTS funding_requests (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    type VARCHAR(20) NOT NULL,              -- deposit, withdrawal
+    amount BIGINT NOT NULL,                 -- 分
+    status VARCHAR(20) DEFAULT 'pending',   -- pending, approved, rejected
+    method VARCHAR(20),
+    account VARCHAR(100),
+    note VARCHAR(255),
+    reviewed_by_id INTEGER REFERENCES admin_users(id),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    review_note VARCHAR(255)
+);
+
+CREATE INDEX idx_funding_requests_deleted_at ON funding_requests(deleted_at);
+CREATE INDEX idx_funding_requests_user_id ON funding_requests(user_id);
+CREATE INDEX idx_funding_requests_type ON funding_requests(type);
+CREATE INDEX idx_funding_requests_status ON funding_requests(status);
+CREATE INDEX idx_funding_requests_reviewed_by_id ON funding_requests(reviewed_by_id);
+
+-- ========================================
+-- Balance Adjustments (人工调账)
+-- ========================================
+
+CREATE TABLE IF NOT EXISTS balance_adjustments (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    amount BIGINT NOT NULL,                 -- 分, 正数加款, 负数扣款
+    reason VARCHAR(20) NOT NULL,            -- compensation, correction, promotion, recovery, other
+    note VARCHAR(255) NOT NULL,
+    status VARCHAR(20) DEFAULT 'pending',   -- pending, applied, rejected
+    requested_by_id INTEGER NOT NULL REFERENCES admin_users(id),
+    reviewed_by_id INTEGER REFERENCES admin_users(id),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    review_note VARCHAR(255)
+);
+
+CREATE INDEX idx_balance_adjustments_deleted_at ON balance_adjustments(deleted_at);
+CREATE INDEX idx_balance_adjustments_user_id ON balance_adjustments(user_id);
+CREATE INDEX idx_balance_adjustments_reason ON balance_adjustments(reason);
+CREATE INDEX idx_balance_adjustments_status ON balance_adjustments(status);
+CREATE INDEX idx_balance_adjustments_requested_by_id ON balance_adjustments(requested_by_id);
+CREATE INDEX idx_balance_adjustments_reviewed_by_id ON balance_adjustments(reviewed_by_id);
+
+-- ========================================
+-- Referral Commissions (推荐佣金, 每个推荐人每天一条, 事后变动追加修订)
+-- ========================================
+
+CREATE TABLE IF NOT EXISTS referral_commissions (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    referrer_id INTEGER NOT NULL REFERENCES users(id),
+    date VARCHAR(10) NOT NULL,              -- YYYY-MM-DD, 下线投注日期
+    revision INTEGER DEFAULT 0,             -- 0 为首次结算, 之后为差额修订
+    referral_count INTEGER DEFAULT 0,
+    customer_loss BIGINT NOT NULL,          -- 分, 投注额 - 派彩 (修订为差额), 可为负
+    rate BIGINT NOT NULL,                   -- 万分之一
+    amount BIGINT NOT NULL,                 -- 分, 向下取整 (修订为差额, 可为负)
+    status VARCHAR(20) DEFAULT 'pending',   -- pending, paid
+    paid_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_referral_commissions_day ON referral_commissions(referrer_id, date, revision);
+CREATE INDEX idx_referral_commissions_deleted_at ON referral_commissions(deleted_at);
+CREATE INDEX idx_referral_commissions_status ON referral_commissions(status);
+
+-- ========================================
+-- Round Corrections (开奖更正审计)
+-- ========================================
+
+CREATE TABLE IF NOT EXISTS round_corrections (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    round_id INTEGER NOT NULL REFERENCES pc28_rounds(id),
+    admin_id INTEGER REFERENCES admin_users(id),
+    reason VARCHAR(255),
+    old_keno_data JSONB,
+    old_result_a INTEGER,
+    old_result_b INTEGER,
+    old_result_c INTEGER,
+    old_sum INTEGER,
+    new_keno_data JSONB,
+    new_result_a INTEGER,
+    new_result_b INTEGER,
+    new_result_c INTEGER,
+    new_sum INTEGER,
+    affected_bets INTEGER DEFAULT 0,
+    old_payout BIGINT DEFAULT 0,
+    new_payout BIGINT DEFAULT 0
+);
+
+CREATE INDEX idx_round_corrections_deleted_at ON round_corrections(deleted_at);
+CREATE INDEX idx_round_corrections_round_id ON round_corrections(round_id);
+CREATE INDEX idx_round_corrections_admin_id ON round_corrections(admin_id);
+
+-- ========================================
+-- Game Settings (游戏设置, 单行 id = 1)
+-- ========================================
+
+CREATE TABLE IF NOT EXISTS game_settings (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    round_duration INTEGER NOT NULL,
+    betting_window INTEGER NOT NULL,
+    bet_cutoff INTEGER NOT NULL DEFAULT 2,  -- 截止前停止投注的安全余量 (秒)
+    bet_cancel BOOLEAN DEFAULT TRUE,        -- 是否允许玩家撤单
+    cancel_limit INTEGER DEFAULT 0,         -- 每位玩家每日撤单次数上限, 0 不限
+    min_bet BIGINT NOT NULL,                -- 分
+    max_bet BIGINT NOT NULL,                -- 分
+    odds JSONB,
+    number_odds JSONB,
+    updated_by_id INTEGER REFERENCES admin_users(id)
+);
+
+CREATE INDEX idx_game_settings_deleted_at ON game_settings(deleted_at);
+
+-- ========================================
+-- Recovery Runs (启动恢复记录)
+-- ========================================
+
+CREATE TABLE IF NOT EXISTS recovery_runs (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    policy VARCHAR(20),
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    settled INTEGER DEFAULT 0,
+    voided INTEGER DEFAULT 0,
+    failed INTEGER DEFAULT 0,
+    details JSONB DEFAULT '[]'  -- 每个过期轮次的处理结果
+);
+
+CREATE INDEX idx_recovery_runs_deleted_at ON recovery_runs(deleted_at);
 
 -- ========================================
 -- Comments
@@ -127,5 +374,14 @@ COMMENT ON COLUMN users.operator_id IS '归属运营者';
 COMMENT ON COLUMN users.referrer_id IS '邀请人';
 COMMENT ON COLUMN users.invite_code IS '用户邀请码';
 
+COMMENT ON TABLE game_rooms IS '游戏房间表';
 COMMENT ON TABLE pc28_rounds IS 'PC28游戏轮次表';
 COMMENT ON TABLE pc28_bets IS 'PC28投注表';
+COMMENT ON COLUMN pc28_bets.chase_plan_id IS '所属追号计划';
+COMMENT ON TABLE chase_plans IS '追号计划表';
+COMMENT ON TABLE wallet_transactions IS '钱包账变记录 (与余额变动同事务写入)';
+COMMENT ON COLUMN wallet_transactions.amount IS '变动金额, 正为入账, 负为出账';
+COMMENT ON COLUMN wallet_transactions.balance_after IS '变动后余额';
+COMMENT ON COLUMN chase_plans.reserved IS '创建时冻结的全部期数投注额';
+COMMENT ON COLUMN chase_plans.refunded IS '提前结束时退回的未下注金额';
+COMMENT ON TABLE round_corrections IS '开奖结果更正审计表';
@@ -45,34 +45,45 @@ ON CONFLICT (code) DO NOTHING;
 -- 玩家用户
 -- ========================================
 
--- 密码: test123 (bcrypt hash)
+-- 密码: test123 (bcrypt hash); 余额以分为单位
 INSERT INTO users (username, password, balance, role, operator_id, referrer_id, invite_code) VALUES
-('player001', '$2a$10$rqV.WTNBGeMqnjrMd4ObuOY7A0YnBBLq8EW2LL0F8dJPWJEVVaD5G', 10000.00, 'user', 1, NULL, 'a1b2c3d4'),
-('player002', '$2a$10$rqV.WTNBGeMqnjrMd4ObuOY7A0YnBBLq8EW2LL0F8dJPWJEVVaD5G', 5000.00, 'user', 1, 1, 'e5f6g7h8'),
-('player003', '$2a$10$rqV.WTNBGeMqnjrMd4ObuOY7A0YnBBLq8EW2LL0F8dJPWJEVVaD5G', 20000.00, 'user', 2, NULL, 'i9j0k1l2'),
-('player004', '$2a$10$rqV.WTNBGeMqnjrMd4ObuOY7A0YnBBLq8EW2LL0F8dJPWJEVVaD5G', 8888.00, 'user', 3, NULL, 'm3n4o5p6'),
-('player005', '$2a$10$rqV.WTNBGeMqnjrMd4ObuOY7A0YnBBLq8EW2LL0F8dJPWJEVVaD5G', 15000.00, 'user', 1, 1, 'q7r8s9t0')
+('player001', '$2a$10$rqV.WTNBGeMqnjrMd4ObuOY7A0YnBBLq8EW2LL0F8dJPWJEVVaD5G', 1000000, 'user', 1, NULL, 'a1b2c3d4'),
+('player002', '$2a$10$rqV.WTNBGeMqnjrMd4ObuOY7A0YnBBLq8EW2LL0F8dJPWJEVVaD5G', 500000, 'user', 1, 1, 'e5f6g7h8'),
+('player003', '$2a$10$rqV.WTNBGeMqnjrMd4ObuOY7A0YnBBLq8EW2LL0F8dJPWJEVVaD5G', 2000000, 'user', 2, NULL, 'i9j0k1l2'),
+('player004', '$2a$10$rqV.WTNBGeMqnjrMd4ObuOY7A0YnBBLq8EW2LL0F8dJPWJEVVaD5G', 888800, 'user', 3, NULL, 'm3n4o5p6'),
+('player005', '$2a$10$rqV.WTNBGeMqnjrMd4ObuOY7A0YnBBLq8EW2LL0F8dJPWJEVVaD5G', 1500000, 'user', 1, 1, 'q7r8s9t0')
 ON CONFLICT (username) DO NOTHING;
 
+-- ========================================
+-- 游戏房间
+-- ========================================
+
+INSERT INTO game_rooms (code, name, issue_prefix, round_duration, betting_window, status, sort_order) VALUES
+('pc28', 'PC28 1分钟', '', 0, 0, 'active', 1),
+('pc28_3m', 'PC28 3分钟', 'B', 180, 170, 'active', 2),
+('pc28_5m', 'PC28 5分钟', 'C', 300, 290, 'active', 3)
+ON CONFLICT (code) DO NOTHING;
+
 -- ========================================
 -- 历史轮次
 -- ========================================
 
-INSERT INTO pc28_rounds (issue_number, keno_data, result_a, result_b, result_c, sum, open_time, close_time, status) VALUES
-('20260122120001', '[1,5,8,12,15,18,22,25,28,32,35,38,42,45,48,52,55,58,62,65]', 9, 0, 3, 12, '2026-01-22 12:00:00+08', '2026-01-22 12:00:55+08', 'settled'),
-('20260122120101', '[3,7,11,14,17,21,24,27,31,34,37,41,44,47,51,54,57,61,64,67]', 3, 4, 4, 11, '2026-01-22 12:01:00+08', '2026-01-22 12:01:55+08', 'settled'),
-('20260122120201', '[2,6,10,13,16,20,23,26,30,33,36,40,43,46,50,53,56,60,63,66]', 7, 8, 8, 23, '2026-01-22 12:02:00+08', '2026-01-22 12:02:55+08', 'settled')
+INSERT INTO pc28_rounds (room_id, issue_number, keno_data, result_a, result_b, result_c, sum, open_time, close_time, status) VALUES
+((SELECT id FROM game_rooms WHERE code = 'pc28'), '20260122120001', '[1,5,8,12,15,18,22,25,28,32,35,38,42,45,48,52,55,58,62,65]', 9, 0, 3, 12, '2026-01-22 12:00:00+08', '2026-01-22 12:00:55+08', 'settled'),
+((SELECT id FROM game_rooms WHERE code = 'pc28'), '20260122120101', '[3,7,11,14,17,21,24,27,31,34,37,41,44,47,51,54,57,61,64,67]', 3, 4, 4, 11, '2026-01-22 12:01:00+08', '2026-01-22 12:01:55+08', 'settled'),
+((SELECT id FROM game_rooms WHERE code = 'pc28'), '20260122120201', '[2,6,10,13,16,20,23,26,30,33,36,40,43,46,50,53,56,60,63,66]', 7, 8, 8, 23, '2026-01-22 12:02:00+08', '2026-01-22 12:02:55+08', 'settled')
 ON CONFLICT (issue_number) DO NOTHING;
 
 -- ========================================
 -- 测试投注
 -- ========================================
 
+-- 金额以分, 赔率以万分之一为单位
 INSERT INTO pc28_bets (user_id, round_id, bet_type, bet_value, amount, odds, status, win_amount) VALUES
-(1, 1, 'small', 0, 100.00, 1.95, 'won', 195.00),
-(1, 1, 'odd', 0, 50.00, 1.95, 'lost', 0.00),
-(2, 2, 'number', 11, 10.00, 9.80, 'won', 98.00),
-(3, 3, 'big', 0, 500.00, 1.95, 'won', 975.00)
+(1, 1, 'small', 0, 10000, 19500, 'won', 19500),
+(1, 1, 'odd', 0, 5000, 19500, 'lost', 0),
+(2, 2, 'number', 11, 1000, 98000, 'won', 9800),
+(3, 3, 'big', 0, 50000, 19500, 'won', 97500)
 ON CONFLICT DO NOTHING;
 
 -- ========================================
@@ -10,6 +10,7 @@ import Game from './pages/Game';
 import History from './pages/History';
 import Profile from './pages/Profile';
 import Stats from './pages/Stats';
+import Wallet from './pages/Wallet';
 import './App.css';
 
 // Protected route wrapper with Layout
@@ -87,6 +88,14 @@ const router = createBrowserRouter([
       </ProtectedRoute>
     ),
   },
+  {
+    path: '/wallet',
+    element: (
+      <ProtectedRoute>
+        <Wallet />
+      </ProtectedRoute>
+    ),
+  },
 ]);
 
 function App() {
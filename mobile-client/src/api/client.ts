@@ -137,10 +137,14 @@ export const authApi = {
 // Game API (Public)
 // ==========================================
 
+const roomQuery = (room?: string) => (room ? `?room=${encodeURIComponent(room)}` : '');
+
 export const gameApi = {
-    getCurrentRound: () => request<any>('/api/v1/games/pc28/round/current'),
-    getHistory: () => request<any[]>('/api/v1/games/pc28/history'),
-    getOdds: () => request<Record<string, number>>('/api/v1/games/pc28/odds'),
+    getRooms: () => request<{ code: string; name: string; round_duration: number; betting_window: number }[]>('/api/v1/games/pc28/rooms'),
+    getCurrentRound: (room?: string) => request<any>(`/api/v1/games/pc28/round/current${roomQuery(room)}`),
+    getHistory: (room?: string) => request<any[]>(`/api/v1/games/pc28/history${roomQuery(room)}`),
+    getSchedule: (room?: string) => request<any[]>(`/api/v1/games/pc28/schedule${roomQuery(room)}`),
+    getOdds: (room?: string) => request<{ odds: Record<string, number>; number_odds: Record<string, number> }>(`/api/v1/games/pc28/odds${roomQuery(room)}`),
 };
 
 // ==========================================
@@ -153,13 +157,61 @@ export const betApi = {
             method: 'POST',
             body: JSON.stringify(data),
         }, true),
+    // All bets of a slip are placed together or not at all
+    placeBetSlip: (data: { room?: string; bets: { round_id: number; bet_type: string; bet_value?: number; amount: number }[] }) =>
+        request<{ bets: any[]; total: number }>('/api/v1/bets/slip', {
+            method: 'POST',
+            body: JSON.stringify(data),
+        }, true),
     getUserBets: () => request<any[]>('/api/v1/bets', {}, true),
+    // Allowed while the round is open and before the cutoff
+    cancelBet: (id: number) =>
+        request<{ bet: any; balance: number }>(`/api/v1/bets/${id}`, { method: 'DELETE' }, true),
+    createChase: (data: { room?: string; bet_type: string; bet_value?: number; amount: number; rounds: number; multiplier?: number; stop_on_win?: boolean }) =>
+        request<any>('/api/v1/bets/chase', {
+            method: 'POST',
+            body: JSON.stringify(data),
+        }, true),
+    getChasePlans: () => request<any[]>('/api/v1/bets/chase', {}, true),
+    getChasePlan: (id: number) => request<any>(`/api/v1/bets/chase/${id}`, {}, true),
+    cancelChase: (id: number) =>
+        request<any>(`/api/v1/bets/chase/${id}`, { method: 'DELETE' }, true),
 };
 
 // ==========================================
 // Player API (Authenticated)
 // ==========================================
 
+export interface WalletTransaction {
+    ID: number;
+    type: string;           // bet, payout, refund, bet_cancel, ...
+    amount: number;         // 正为入账, 负为出账
+    balance_after: number;
+    bet_id?: number;
+    round_id?: number;
+    note?: string;
+    CreatedAt: string;
+}
+
+export interface FundingRequest {
+    ID: number;
+    type: 'deposit' | 'withdrawal';
+    amount: number;
+    status: 'pending' | 'approved' | 'rejected';
+    method: string;
+    account: string;
+    note?: string;
+    review_note?: string;
+    CreatedAt: string;
+}
+
+export interface FundingSubmission {
+    amount: number;
+    method: string;
+    account: string;         // 充值为付款凭证/账户, 提现为收款账户
+    note?: string;
+}
+
 export interface ReferralUser {
     id: number;
     username: string;
@@ -173,7 +225,8 @@ export interface ReferralStats {
     total_referrals: number;     // 下线总人数
     active_referrals: number;    // 活跃下线
     total_customer_loss: number; // 总客损
-    total_commission: number;    // 总佣金
+    total_commission: number;    // 总佣金 (含待领取)
+    pending_commission: number;  // 待领取佣金
     commission_rate: number;     // 佣金比例
 }
 
@@ -182,6 +235,7 @@ export interface DailyEarning {
     customer_loss: number;
     commission: number;
     referral_count: number;
+    status: 'pending' | 'paid';
 }
 
 export interface EarningsSummary {
@@ -204,14 +258,48 @@ export const playerApi = {
         if (params.toString()) url += '?' + params.toString();
         return request<EarningsSummary>(url, {}, true);
     },
+    // 领取全部待领取佣金 (每日结算不自动入账时)
+    claimCommission: () =>
+        request<{ claimed: number; count: number; balance: number }>('/api/v1/player/commissions/claim', {
+            method: 'POST',
+        }, true),
+    getTransactions: (params: { type?: string; page?: number; page_size?: number } = {}) => {
+        const query = new URLSearchParams();
+        if (params.type) query.append('type', params.type);
+        if (params.page) query.append('page', String(params.page));
+        if (params.page_size) query.append('page_size', String(params.page_size));
+        const qs = query.toString();
+        return request<{ data: WalletTransaction[]; total: number; page: number; page_size: number }>(
+            `/api/v1/player/transactions${qs ? `?${qs}` : ''}`, {}, true);
+    },
+    // 充值审核通过后入账; 提现提交时即冻结金额
+    deposit: (data: FundingSubmission) =>
+        request<{ request: FundingRequest; balance: number }>('/api/v1/player/deposits', {
+            method: 'POST',
+            body: JSON.stringify(data),
+        }, true),
+    withdraw: (data: FundingSubmission) =>
+        request<{ request: FundingRequest; balance: number }>('/api/v1/player/withdrawals', {
+            method: 'POST',
+            body: JSON.stringify(data),
+        }, true),
+    getFunding: (page = 1) =>
+        request<{ data: FundingRequest[]; total: number; page: number; page_size: number }>(
+            `/api/v1/player/funding?page=${page}`, {}, true),
 };
 
 // ==========================================
 // WebSocket
 // ==========================================
 
-export function createWebSocket(onMessage: (msg: any) => void): WebSocket {
-    const ws = new WebSocket(`${WS_BASE}/ws`);
+// A logged-in player also receives personal messages such as balance_update
+export function createWebSocket(onMessage: (msg: any) => void, room?: string): WebSocket {
+    const params = new URLSearchParams();
+    if (room) params.set('room', room);
+    const token = getToken();
+    if (token) params.set('token', token);
+    const query = params.toString();
+    const ws = new WebSocket(`${WS_BASE}/ws${query ? `?${query}` : ''}`);
 
     ws.onmessage = (event) => {
         try {
@@ -15,7 +15,7 @@ const NUMBERS = Array.from({ length: 28 }, (_, i) => i);
 const CHIPS = [10, 50, 100, 500, 1000];
 
 export default function Game() {
-    const [user] = useAtom(userAtom);
+    const [user, setUser] = useAtom(userAtom);
     const [currentRound, setCurrentRound] = useAtom(currentRoundAtom);
     const [countdown, setCountdown] = useAtom(countdownAtom);
     const [selectedBets, setSelectedBets] = useAtom(selectedBetsAtom);
@@ -44,6 +44,8 @@ export default function Game() {
                 setSelectedBets([]);
             } else if (msg.type === 'round_update') {
                 setCurrentRound(msg.payload);
+            } else if (msg.type === 'balance_update') {
+                setUser((prev) => (prev ? { ...prev, balance: msg.payload.balance } : prev));
             }
         });
 
@@ -58,13 +60,18 @@ export default function Game() {
     const handleConfirmBets = async () => {
         if (!currentRound || selectedBets.length === 0) return;
 
-        for (const bet of selectedBets) {
-            await betApi.placeBet({
+        const res = await betApi.placeBetSlip({
+            bets: selectedBets.map((bet) => ({
                 round_id: currentRound.id,
                 bet_type: bet.type,
                 bet_value: bet.value,
                 amount: bet.amount,
-            });
+            })),
+        });
+        if (res.error) {
+            // Nothing was placed; keep the slip so the player can fix it
+            alert(res.error);
+            return;
         }
         setSelectedBets([]);
     };
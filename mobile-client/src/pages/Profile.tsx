@@ -127,6 +127,15 @@ export default function Profile() {
                         <span className="menu-item-arrow">›</span>
                     </Link>
 
+                    <Link to="/wallet" className="quick-menu-item">
+                        <div className="menu-item-icon">🏦</div>
+                        <div className="menu-item-content">
+                            <span className="menu-item-title">充值提现</span>
+                            <span className="menu-item-desc">提交申请，审核后到账</span>
+                        </div>
+                        <span className="menu-item-arrow">›</span>
+                    </Link>
+
                     <Link to="/history" className="quick-menu-item">
                         <div className="menu-item-icon">📜</div>
                         <div className="menu-item-content">
@@ -1,6 +1,8 @@
 import { useEffect, useState } from 'react';
 import { Link } from 'react-router-dom';
 import { QRCodeSVG } from 'qrcode.react';
+import { useSetAtom } from 'jotai';
+import { playerUserAtom } from '../store/atoms';
 import { playerApi } from '../api/client';
 import type { ReferralUser, ReferralStats, EarningsSummary, DailyEarning } from '../api/client';
 import './Stats.css';
@@ -16,6 +18,8 @@ export default function Stats() {
     const [loading, setLoading] = useState(true);
     const [dateRange, setDateRange] = useState<DateRange>('all');
     const [copied, setCopied] = useState(false);
+    const [claiming, setClaiming] = useState(false);
+    const setUser = useSetAtom(playerUserAtom);
 
     useEffect(() => {
         loadData();
@@ -44,6 +48,20 @@ export default function Stats() {
         setLoading(false);
     };
 
+    const handleClaim = async () => {
+        setClaiming(true);
+        const res = await playerApi.claimCommission();
+        setClaiming(false);
+        if (res.error) {
+            alert(res.error === 'No pending commission' ? '暂无待领取佣金' : res.error);
+            return;
+        }
+        if (res.data) {
+            setUser((prev) => (prev ? { ...prev, balance: res.data!.balance } : prev));
+        }
+        loadData();
+    };
+
     const getDateRange = (range: DateRange) => {
         const now = new Date();
         const end = now.toISOString().split('T')[0];
@@ -141,6 +159,15 @@ export default function Stats() {
                     <div className="title">我的推广收益</div>
                     <div className="amount">{formatMoney(earnings?.total_earnings || 0)}</div>
                     <div className="rate">佣金比例 {((stats?.commission_rate || 0) * 100).toFixed(1)}%</div>
+                    {(stats?.pending_commission || 0) > 0 && (
+                        <div className="commission-claim">
+                            <span>待领取 {formatMoney(stats!.pending_commission)}</span>
+                            <button disabled={claiming} onClick={handleClaim}>
+                                {claiming ? '领取中...' : '领取'}
+                            </button>
+                        </div>
+                    )}
+                    <div className="commission-hint">佣金按下线前一日净亏损每日结算</div>
                 </div>
 
                 {/* Date Filter */}
@@ -172,7 +199,10 @@ export default function Stats() {
                         {earnings.daily_earnings.map((day: DailyEarning) => (
                             <div key={day.date} className="daily-item">
                                 <span className="daily-date">{formatDate(day.date)}</span>
-                                <span className="daily-commission">+{formatMoney(day.commission)}</span>
+                                <span className="daily-commission">
+                                    +{formatMoney(day.commission)}
+                                    {day.status === 'pending' && day.commission > 0 && <small> 待领取</small>}
+                                </span>
                             </div>
                         ))}
                     </div>
@@ -0,0 +1,137 @@
+import { useEffect, useState } from 'react';
+import { Link } from 'react-router-dom';
+import { useAtom } from 'jotai';
+import { playerUserAtom } from '../store/atoms';
+import { playerApi, createWebSocket, type FundingRequest } from '../api/client';
+import './History.css';
+import './Wallet.css';
+
+const statusLabels: Record<string, string> = {
+    pending: '待审核',
+    approved: '已完成',
+    rejected: '已拒绝',
+};
+
+export default function Wallet() {
+    const [user, setUser] = useAtom(playerUserAtom);
+    const [type, setType] = useState<'deposit' | 'withdrawal'>('deposit');
+    const [amount, setAmount] = useState('');
+    const [method, setMethod] = useState('bank');
+    const [account, setAccount] = useState('');
+    const [error, setError] = useState('');
+    const [submitting, setSubmitting] = useState(false);
+    const [requests, setRequests] = useState<FundingRequest[]>([]);
+
+    const load = () => {
+        playerApi.getFunding().then((res) => {
+            if (res.data) setRequests(res.data.data);
+        });
+    };
+
+    useEffect(() => {
+        load();
+        // Reviews happen in the admin panel; refresh when one arrives
+        const ws = createWebSocket((msg) => {
+            if (msg.type === 'funding_update') {
+                load();
+            } else if (msg.type === 'balance_update') {
+                setUser((prev) => (prev ? { ...prev, balance: msg.payload.balance } : prev));
+            }
+        });
+        return () => ws.close();
+    }, []);
+
+    const handleSubmit = async () => {
+        const value = Number(amount);
+        if (!/^\d+(\.\d{1,2})?$/.test(amount) || value <= 0) {
+            setError('请输入有效金额 (最多两位小数)');
+            return;
+        }
+        if (!account) {
+            setError('请填写账户');
+            return;
+        }
+
+        setError('');
+        setSubmitting(true);
+        const submit = type === 'deposit' ? playerApi.deposit : playerApi.withdraw;
+        const res = await submit({ amount: value, method, account });
+        setSubmitting(false);
+
+        if (res.error) {
+            setError(res.error === 'Insufficient balance' ? '余额不足' : res.error);
+            return;
+        }
+        setAmount('');
+        if (res.data) {
+            setUser((prev) => (prev ? { ...prev, balance: res.data!.balance } : prev));
+        }
+        load();
+    };
+
+    return (
+        <div className="history">
+            <header className="history-header">
+                <Link to="/profile" className="back-btn">←</Link>
+                <h1>充值提现</h1>
+                <div style={{ width: 32 }}></div>
+            </header>
+
+            <div className="history-content">
+                <div className="wallet-balance">
+                    <span>账户余额</span>
+                    <strong>¥{(user?.balance || 0).toLocaleString()}</strong>
+                </div>
+
+                <div className="wallet-tabs">
+                    <button className={type === 'deposit' ? 'active' : ''} onClick={() => setType('deposit')}>充值</button>
+                    <button className={type === 'withdrawal' ? 'active' : ''} onClick={() => setType('withdrawal')}>提现</button>
+                </div>
+
+                <div className="wallet-form">
+                    <input type="number" step="0.01" placeholder="金额" value={amount} onChange={(e) => setAmount(e.target.value)} />
+                    <select value={method} onChange={(e) => setMethod(e.target.value)}>
+                        <option value="bank">银行卡</option>
+                        <option value="alipay">支付宝</option>
+                        <option value="wechat">微信</option>
+                    </select>
+                    <input
+                        placeholder={type === 'deposit' ? '付款账户 / 流水号' : '收款账户'}
+                        value={account}
+                        onChange={(e) => setAccount(e.target.value)}
+                    />
+                    {type === 'withdrawal' && <p className="wallet-hint">提交后金额将被冻结，审核拒绝时退回余额</p>}
+                    {error && <p className="wallet-error">{error}</p>}
+                    <button className="wallet-submit" disabled={submitting} onClick={handleSubmit}>
+                        {submitting ? '提交中...' : type === 'deposit' ? '提交充值申请' : '提交提现申请'}
+                    </button>
+                </div>
+
+                {requests.length === 0 ? (
+                    <div className="empty">
+                        <span className="empty-icon">🏦</span>
+                        <p>暂无申请记录</p>
+                    </div>
+                ) : (
+                    <div className="bet-list">
+                        {requests.map((req) => (
+                            <div key={req.ID} className={`bet-card funding-${req.status}`}>
+                                <div className="bet-info">
+                                    <span className="bet-type">{req.type === 'deposit' ? '充值' : '提现'}</span>
+                                    <span className="bet-round">{new Date(req.CreatedAt).toLocaleString()}</span>
+                                </div>
+                                <div className="bet-amount">
+                                    <span className="amount">¥{req.amount.toLocaleString()}</span>
+                                </div>
+                                <div className="bet-result">
+                                    <span className={req.status}>{statusLabels[req.status] || req.status}</span>
+                                    {req.review_note && <span className="bet-round">{req.review_note}</span>}
+                                </div>
+                            </div>
+                        ))}
+                    </div>
+                )}
+            </div>
+        </div>
+    );
+}